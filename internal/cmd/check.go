@@ -0,0 +1,173 @@
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	api "github.com/carabiner-dev/policy/api/v1"
+	v0 "github.com/in-toto/attestation/go/predicates/test_result/v0"
+	"github.com/spf13/cobra"
+
+	"github.com/carabiner-dev/beaker/pkg/beaker"
+)
+
+type checkOptions struct {
+	runOptions
+	policyPath     string
+	verifyKeyPath  string
+	verifyCertPath string
+	skipSignature  bool
+}
+
+// Validates the options in context with arguments
+func (co *checkOptions) Validate(args []string) error {
+	errs := []error{}
+	if co.policyPath == "" {
+		errs = append(errs, errors.New("a policy file is required (--policy)"))
+	}
+	if co.verifyKeyPath != "" && co.verifyCertPath != "" {
+		errs = append(errs, errors.New("--verify-key and --verify-cert are mutually exclusive"))
+	}
+	if len(args) > 0 && co.verifyKeyPath == "" && co.verifyCertPath == "" && !co.skipSignature {
+		errs = append(errs, errors.New("a public key (--verify-key) or certificate (--verify-cert) is required to check the attestation signature, or pass --insecure-skip-signature"))
+	}
+	return errors.Join(errs...)
+}
+
+// AddFlags adds the subcommands flags
+func (co *checkOptions) AddFlags(cmd *cobra.Command) {
+	co.runOptions.AddFlags(cmd)
+	cmd.PersistentFlags().StringVarP(
+		&co.policyPath, "policy", "p", "", "path to the policy file to check the test results against",
+	)
+	cmd.PersistentFlags().StringVar(
+		&co.verifyKeyPath, "verify-key", "", "path to the PEM or GPG public key to verify the signature of the attestation",
+	)
+	cmd.PersistentFlags().StringVar(
+		&co.verifyCertPath, "verify-cert", "", "path to a PEM certificate, its public key verifies the signature of the attestation",
+	)
+	cmd.PersistentFlags().BoolVar(
+		&co.skipSignature, "insecure-skip-signature", false, "accept unsigned attestations and do not check signatures",
+	)
+}
+
+// readResults returns the test results in an existing attestation after
+// verifying its signature.
+func (co *checkOptions) readResults(cmd *cobra.Command, path string) (*v0.TestResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading attestation: %w", err)
+	}
+
+	verifier, err := loadVerifier(co.verifyKeyPath, co.verifyCertPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// The policy decides on failed tests, the result is not checked here
+	v, err := beaker.Verify(cmd.Context(), data, &beaker.VerifyOptions{
+		Verifier: verifier, SkipSignature: co.skipSignature,
+	})
+	switch {
+	case errors.Is(err, beaker.ErrSignature):
+		return nil, &exitError{code: exitBadSignature, err: err}
+	case err != nil && !errors.Is(err, beaker.ErrTestsFailed):
+		return nil, err
+	}
+	return v.Predicate, nil
+}
+
+// printPolicyResult writes the status of the policy and its tenets
+func printPolicyResult(w io.Writer, policy *api.Policy, res *api.Result) {
+	fmt.Fprintf(w, "policy %s: %s\n", res.GetPolicy().GetId(), res.GetStatus())
+	for i, er := range res.GetEvalResults() {
+		name := er.GetId()
+		if title := policy.GetTenets()[i].GetTitle(); title != "" {
+			name = fmt.Sprintf("%s: %s", name, title)
+		}
+		fmt.Fprintf(w, "  %-5s  %s\n", er.GetStatus(), name)
+		if msg := er.GetAssessment().GetMessage(); msg != "" {
+			fmt.Fprintf(w, "         %s\n", msg)
+		}
+		if msg := er.GetError().GetMessage(); msg != "" {
+			fmt.Fprintf(w, "         %s\n", msg)
+		}
+		if guidance := er.GetError().GetGuidance(); guidance != "" && er.GetStatus() != api.StatusPASS {
+			fmt.Fprintf(w, "         %s\n", guidance)
+		}
+	}
+}
+
+func addCheck(parentCmd *cobra.Command) {
+	opts := &checkOptions{}
+	checkCmd := &cobra.Command{
+		Short: "checks test results against a policy",
+		Long: fmt.Sprintf(`checks test results against a policy
+
+The policy is read in the format of the carabiner policy framework. Its
+tenets are CEL (cel@v0) expressions evaluated against the test-result
+predicate.
+
+When an attestation file is passed, its signature is verified with
+--verify-key or --verify-cert (or skipped with --insecure-skip-signature)
+and its test results are checked. Otherwise the tests are run as in
+beaker run, the attestation is written and the new results are checked.
+
+beaker exits with %d when the policy fails and with %d when the signature
+of the attestation is invalid or it is not signed. Soft failures of
+policies that are not enforced are reported without failing.
+`, exitPolicyFailed, exitBadSignature),
+		Use:               "check --policy policy.json [attestation.json]",
+		Args:              cobra.MaximumNArgs(1),
+		SilenceUsage:      false,
+		SilenceErrors:     true,
+		PersistentPreRunE: initLogging,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			opts.defaultOutputPath(cmd)
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.Validate(args); err != nil {
+				return err
+			}
+
+			policy, err := beaker.LoadPolicy(opts.policyPath)
+			if err != nil {
+				return err
+			}
+
+			var att *v0.TestResult
+			var runErr error
+			if len(args) > 0 {
+				cmd.SilenceUsage = true
+				att, err = opts.readResults(cmd, args[0])
+				if err != nil {
+					return err
+				}
+			} else {
+				att, runErr = opts.execute(cmd)
+				if runErr != nil && !errors.Is(runErr, beaker.ErrTimeout) {
+					return runErr
+				}
+			}
+
+			res, err := beaker.CheckPolicy(policy, att)
+			if err != nil {
+				return fmt.Errorf("checking policy: %w", err)
+			}
+			printPolicyResult(cmd.OutOrStdout(), policy, res)
+
+			if res.GetStatus() == api.StatusFAIL {
+				return &exitError{code: exitPolicyFailed, err: beaker.ErrPolicyFailed}
+			}
+			return runErr
+		},
+	}
+	opts.AddFlags(checkCmd)
+	parentCmd.AddCommand(checkCmd)
+}
@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckOptionsValidate(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name    string
+		flags   []string
+		args    []string
+		mustErr bool
+	}{
+		{name: "run", flags: []string{"--policy=policy.json"}},
+		{name: "no-policy", flags: []string{}, mustErr: true},
+		{name: "attestation-no-key", flags: []string{"--policy=policy.json"}, args: []string{"tests.intoto.json"}, mustErr: true},
+		{name: "attestation-key", flags: []string{"--policy=policy.json", "--verify-key=beaker.pub"}, args: []string{"tests.intoto.json"}},
+		{name: "attestation-cert", flags: []string{"--policy=policy.json", "--verify-cert=beaker.crt"}, args: []string{"tests.intoto.json"}},
+		{name: "attestation-skip", flags: []string{"--policy=policy.json", "--insecure-skip-signature"}, args: []string{"tests.intoto.json"}},
+		{name: "key-and-cert", flags: []string{"--policy=policy.json", "--verify-key=beaker.pub", "--verify-cert=beaker.crt"}, mustErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			co := &checkOptions{}
+			cmd := &cobra.Command{}
+			co.AddFlags(cmd)
+			require.NoError(t, cmd.ParseFlags(tc.flags))
+
+			err := co.Validate(tc.args)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
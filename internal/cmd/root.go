@@ -4,7 +4,9 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"os"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -39,9 +41,16 @@ func Execute() {
 		"log-level", "info", fmt.Sprintf("the logging verbosity, either %s", log.LevelNames()),
 	)
 	addRun(rootCmd)
+	addVerify(rootCmd)
+	addCheck(rootCmd)
 	rootCmd.AddCommand(version.WithFont("doom"))
 
 	if err := rootCmd.Execute(); err != nil {
+		var exitErr *exitError
+		if errors.As(err, &exitErr) {
+			logrus.Error(err.Error())
+			os.Exit(exitErr.code)
+		}
 		logrus.Fatal("RIP: " + err.Error())
 	}
 }
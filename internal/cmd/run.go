@@ -7,8 +7,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
 
+	v0 "github.com/in-toto/attestation/go/predicates/test_result/v0"
 	"github.com/spf13/cobra"
 	"sigs.k8s.io/release-utils/helpers"
 
@@ -17,11 +25,51 @@ import (
 
 type runOptions struct {
 	configFile string
+	runner     string
 	workDir    string
 	attest     bool
 	outputPath string
+	timeout    time.Duration
+	exitPolicy string
+	sign       bool
+	keyPath    string
+	certPath   string
+	format     string
+	logPath    string
+
+	// Source mode, the archive hashed in archive mode and the patterns
+	// ignored when hashing the codebase in dir mode.
+	source        string
+	sourceArchive string
+	sourceIgnore  []string
+
+	// Remote recorded in the repository descriptor and URI overriding it
+	remote  string
+	repoURI string
+
+	// Pattern of the tags used for the version and its format
+	tagPattern    string
+	versionFormat string
+
+	// requireClean refuses to attest a dirty git working tree
+	requireClean bool
+
+	// Custom subjects as name=algorithm:digest and files to hash as subjects
+	subjects     []string
+	subjectFiles []string
+
+	// loadedConfig is the path of the configuration file read, if any
+	loadedConfig string
 }
 
+const (
+	// keyPassphraseEnv is the environment variable read to decrypt the key
+	keyPassphraseEnv = "BEAKER_KEY_PASSPHRASE"
+
+	// defaultBundlePath is the output path of bundles when not set
+	defaultBundlePath = "tests.sigstore.json"
+)
+
 // Validates the options in context with arguments
 func (ro *runOptions) Validate() error {
 	errs := []error{}
@@ -32,19 +80,161 @@ func (ro *runOptions) Validate() error {
 	if ro.outputPath == "" {
 		errs = append(errs, errors.New("output path is required"))
 	}
+
+	if ro.timeout < 0 {
+		errs = append(errs, errors.New("timeout cannot be negative"))
+	}
+
+	if !slices.Contains(beaker.ExitPolicies, beaker.ExitPolicy(ro.exitPolicy)) {
+		errs = append(errs, fmt.Errorf("invalid exit policy %q", ro.exitPolicy))
+	}
+
+	if ro.sign {
+		if ro.keyPath == "" {
+			errs = append(errs, errors.New("signing requires a key (--key)"))
+		}
+		if !ro.attest {
+			errs = append(errs, errors.New("signing requires the output to be an attestation (--attest)"))
+		}
+	}
+
+	if !slices.Contains(beaker.OutputFormats, beaker.OutputFormat(ro.format)) {
+		errs = append(errs, fmt.Errorf("invalid output format %q", ro.format))
+	}
+
+	if beaker.OutputFormat(ro.format) == beaker.FormatBundle && !ro.sign {
+		errs = append(errs, errors.New("the bundle format requires signing (--sign)"))
+	}
+
+	if ro.certPath != "" && !ro.sign {
+		errs = append(errs, errors.New("a certificate can only be used when signing (--sign)"))
+	}
+
+	if !slices.Contains(beaker.SourceModes, beaker.SourceMode(ro.source)) {
+		errs = append(errs, fmt.Errorf("invalid source mode %q", ro.source))
+	}
+
+	if beaker.SourceMode(ro.source) == beaker.SourceArchive && ro.sourceArchive == "" {
+		errs = append(errs, errors.New("the archive source mode requires an archive (--source-archive)"))
+	}
+
+	if beaker.SourceMode(ro.source) == beaker.SourceDirectory && ro.sourceArchive != "" {
+		errs = append(errs, errors.New("a source archive cannot be used with the dir source mode"))
+	}
+
+	if !slices.Contains(beaker.VersionFormats, beaker.VersionFormat(ro.versionFormat)) {
+		errs = append(errs, fmt.Errorf("invalid version format %q", ro.versionFormat))
+	}
+
+	if ro.remote != "" && ro.repoURI != "" {
+		errs = append(errs, errors.New("--remote and --repo-uri cannot be used together"))
+	}
+
+	for _, spec := range ro.subjects {
+		if _, err := beaker.ParseSubject(spec); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if (len(ro.subjects) > 0 || len(ro.subjectFiles) > 0) && !ro.attest {
+		errs = append(errs, errors.New("custom subjects require the output to be an attestation (--attest)"))
+	}
 	return errors.Join(errs...)
 }
 
+// loadConfig reads the configuration file. When the path was not set
+// explicitly, a missing file is not an error and the configuration is
+// looked up in the working directory. Settings in the file only apply
+// when their flags were not set in the command line.
+func (ro *runOptions) loadConfig(cmd *cobra.Command) (*beaker.Config, error) {
+	path := ro.configFile
+	if !cmd.Flags().Changed("config") {
+		path = filepath.Join(ro.workDir, beaker.DefaultConfigFile)
+		if !helpers.Exists(path) {
+			return nil, nil
+		}
+	}
+
+	conf, err := beaker.LoadConfig(path)
+	if err != nil {
+		return nil, fmt.Errorf("loading configuration: %w", err)
+	}
+	ro.loadedConfig = path
+
+	if conf.Output != "" && !cmd.Flags().Changed("output") {
+		ro.outputPath = conf.Output
+	}
+	if conf.Attest != nil && !cmd.Flags().Changed("attest") {
+		ro.attest = *conf.Attest
+	}
+	if conf.Timeout != 0 && !cmd.Flags().Changed("timeout") {
+		ro.timeout = conf.Timeout
+	}
+	if conf.ExitPolicy != "" && !cmd.Flags().Changed("exit-policy") {
+		ro.exitPolicy = string(conf.ExitPolicy)
+	}
+	if conf.Log != "" && !cmd.Flags().Changed("log") {
+		ro.logPath = conf.Log
+	}
+	if conf.Source != "" && !cmd.Flags().Changed("source") {
+		ro.source = string(conf.Source)
+	}
+	if conf.SourceArchive != "" && !cmd.Flags().Changed("source-archive") {
+		ro.sourceArchive = conf.SourceArchive
+	}
+	if len(conf.SourceIgnore) > 0 && !cmd.Flags().Changed("source-ignore") {
+		ro.sourceIgnore = conf.SourceIgnore
+	}
+	if conf.Remote != "" && !cmd.Flags().Changed("remote") {
+		ro.remote = conf.Remote
+	}
+	if conf.RepoURI != "" && !cmd.Flags().Changed("repo-uri") {
+		ro.repoURI = conf.RepoURI
+	}
+	if conf.TagPattern != "" && !cmd.Flags().Changed("tag-pattern") {
+		ro.tagPattern = conf.TagPattern
+	}
+	if conf.VersionFormat != "" && !cmd.Flags().Changed("version-format") {
+		ro.versionFormat = string(conf.VersionFormat)
+	}
+	if conf.RequireClean && !cmd.Flags().Changed("require-clean") {
+		ro.requireClean = conf.RequireClean
+	}
+	return conf, nil
+}
+
+// selectRunner filters the runners in the configuration when one was
+// forced with --runner. If the configuration does not define it, the
+// runner is used with its defaults.
+func (ro *runOptions) selectRunner(conf *beaker.Config) *beaker.Config {
+	if ro.runner == "" {
+		return conf
+	}
+
+	selected := &beaker.Config{Runners: []beaker.RunnerConfig{}}
+	if conf != nil {
+		for _, rc := range conf.Runners {
+			if rc.Name == ro.runner {
+				selected.Runners = append(selected.Runners, rc)
+			}
+		}
+	}
+	if len(selected.Runners) == 0 {
+		selected.Runners = append(selected.Runners, beaker.RunnerConfig{Name: ro.runner})
+	}
+	return selected
+}
+
 // AddFlags adds the subcommands flags
 func (ro *runOptions) AddFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringVarP(
-		&ro.configFile, "runner", "r", "", "test runner to configure",
+		&ro.runner, "runner", "r", "", fmt.Sprintf("force a test runner (%s)", strings.Join(beaker.Runners(), ", ")),
 	)
 	cmd.PersistentFlags().StringVarP(
 		&ro.workDir, "dir", "d", ".", "path to codebase",
 	)
 	cmd.PersistentFlags().StringVarP(
-		&ro.configFile, "config", "c", ".beaker.yaml", "path to configuration file",
+		&ro.configFile, "config", "c", beaker.DefaultConfigFile, "path to configuration file",
 	)
 	cmd.PersistentFlags().BoolVarP(
 		&ro.attest, "attest", "a", true, "output the entire in-toto statement (instead of predicate)",
@@ -52,6 +242,188 @@ func (ro *runOptions) AddFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringVarP(
 		&ro.outputPath, "output", "o", "tests.intoto.json", "path to file to write the predicate or attestation",
 	)
+	cmd.PersistentFlags().StringVar(
+		&ro.exitPolicy, "exit-policy", string(beaker.ExitPolicyError),
+		"how the runner exit status is reconciled with the test results: ignore, error (fail when the runner failed without failing tests) or strict (error also requires tests to be found)",
+	)
+	cmd.PersistentFlags().DurationVar(
+		&ro.timeout, "timeout", 0, "maximum time the tests can run, the processes are killed when it expires (0 means no limit)",
+	)
+	cmd.PersistentFlags().StringVar(
+		&ro.logPath, "log", "", "path to write the raw output of the test runners, referenced from the attestation by its digest",
+	)
+	cmd.PersistentFlags().BoolVar(
+		&ro.sign, "sign", false, "sign the attestation, wrapping it in a DSSE envelope",
+	)
+	cmd.PersistentFlags().StringVar(
+		&ro.keyPath, "key", "", fmt.Sprintf("path to the PEM or GPG private key used to sign (encrypted keys are decrypted with $%s)", keyPassphraseEnv),
+	)
+	cmd.PersistentFlags().StringVar(
+		&ro.certPath, "cert", "", "path to the PEM certificate of the signing key, included in bundles",
+	)
+	cmd.PersistentFlags().StringVar(
+		&ro.format, "format", string(beaker.FormatJSON), "output format: json (predicate, statement or DSSE envelope) or bundle (sigstore bundle)",
+	)
+	cmd.PersistentFlags().StringVar(
+		&ro.source, "source", string(beaker.SourceGit),
+		"how the tested sources are identified in the subject: git (the checked out commit), dir (a digest of the codebase files) or archive (the digest of --source-archive)",
+	)
+	cmd.PersistentFlags().StringVar(
+		&ro.sourceArchive, "source-archive", "", "path to the archive the codebase was extracted from, used as the subject",
+	)
+	cmd.PersistentFlags().StringSliceVar(
+		&ro.sourceIgnore, "source-ignore", []string{}, "patterns of files that are not part of the sources, excluded from the codebase digest and the dirty tree check",
+	)
+	cmd.PersistentFlags().StringVar(
+		&ro.remote, "remote", "", "git remote recorded as the repository URL (defaults to upstream, origin or the first remote by name)",
+	)
+	cmd.PersistentFlags().StringVar(
+		&ro.repoURI, "repo-uri", "", "repository URL to record in the attestation instead of the URL of a remote",
+	)
+	cmd.PersistentFlags().StringVar(
+		&ro.tagPattern, "tag-pattern", "", "glob the git tags must match to compute the version of the sources, such as 'v*' (defaults to all tags)",
+	)
+	cmd.PersistentFlags().StringVar(
+		&ro.versionFormat, "version-format", string(beaker.VersionFormatSemver),
+		"format of the version of commits past the latest tag: semver (v1.0.1-3+2bce182a) or describe (v1.0.1-3-g2bce182)",
+	)
+	cmd.PersistentFlags().BoolVar(
+		&ro.requireClean, "require-clean", false, "refuse to run the tests when the git working tree has uncommitted changes",
+	)
+	cmd.PersistentFlags().StringArrayVar(
+		&ro.subjects, "subject", []string{}, "attest the tests against a subject (name=algorithm:digest) instead of the sources, can be repeated",
+	)
+	cmd.PersistentFlags().StringArrayVar(
+		&ro.subjectFiles, "subject-file", []string{}, "attest the tests against the sha256 digest of a file instead of the sources, can be repeated",
+	)
+}
+
+// defaultOutputPath gives bundles the conventional sigstore extension
+// when the output path was not set explicitly.
+func (ro *runOptions) defaultOutputPath(cmd *cobra.Command) {
+	if beaker.OutputFormat(ro.format) == beaker.FormatBundle && !cmd.Flags().Changed("output") {
+		ro.outputPath = defaultBundlePath
+	}
+}
+
+// launcherOptions returns the options to create the launcher
+func (ro *runOptions) launcherOptions(w io.Writer) []beaker.OptFn {
+	opts := []beaker.OptFn{
+		beaker.WithWriter(w),
+		beaker.WithAttest(ro.attest),
+		beaker.WithWorkDir(ro.workDir),
+		beaker.WithTimeout(ro.timeout),
+		beaker.WithExitPolicy(beaker.ExitPolicy(ro.exitPolicy)),
+		beaker.WithFormat(beaker.OutputFormat(ro.format)),
+		beaker.WithLogPath(ro.logPath),
+		beaker.WithConfigFile(ro.loadedConfig),
+		beaker.WithSource(beaker.SourceMode(ro.source)),
+		beaker.WithSourceIgnore(slices.Concat(ro.sourceIgnore, ro.generatedFiles())...),
+		beaker.WithRequireClean(ro.requireClean),
+		beaker.WithRemote(ro.remote),
+		beaker.WithRepoURI(ro.repoURI),
+		beaker.WithTagPattern(ro.tagPattern),
+		beaker.WithVersionFormat(beaker.VersionFormat(ro.versionFormat)),
+	}
+	if ro.sourceArchive != "" {
+		opts = append(opts, beaker.WithSourceArchive(ro.sourceArchive))
+	}
+	for _, spec := range ro.subjects {
+		opts = append(opts, beaker.WithSubject(spec))
+	}
+	for _, path := range ro.subjectFiles {
+		opts = append(opts, beaker.WithSubjectFile(path))
+	}
+	if ro.sign {
+		opts = append(opts, beaker.WithSigningKey(ro.keyPath, []byte(os.Getenv(keyPassphraseEnv))))
+	}
+	if ro.certPath != "" {
+		opts = append(opts, beaker.WithCertificate(ro.certPath))
+	}
+	return opts
+}
+
+// generatedFiles returns patterns matching the files beaker writes in the
+// codebase, they are not part of the sources and must not change its
+// digest.
+func (ro *runOptions) generatedFiles() []string {
+	patterns := []string{}
+	root, err := filepath.Abs(ro.workDir)
+	if err != nil {
+		return patterns
+	}
+	for _, p := range []string{ro.outputPath, ro.logPath} {
+		if p == "" {
+			continue
+		}
+		abs, err := filepath.Abs(p)
+		if err != nil {
+			continue
+		}
+		rel, err := filepath.Rel(root, abs)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		patterns = append(patterns, "/"+filepath.ToSlash(rel))
+	}
+	return patterns
+}
+
+// execute runs the tests and writes the attestation to the output path.
+// It returns the test results, along ErrTimeout when the tests timed out.
+func (ro *runOptions) execute(cmd *cobra.Command) (*v0.TestResult, error) {
+	// Load the configuration file, if there is one
+	conf, err := ro.loadConfig(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate the options merged with the configuration
+	if err := ro.Validate(); err != nil {
+		return nil, err
+	}
+	cmd.SilenceUsage = true
+
+	f, err := os.Create(ro.outputPath)
+	if err != nil {
+		return nil, fmt.Errorf("opening file: %w", err)
+	}
+
+	defer func() {
+		if err := f.Close(); err != nil {
+			return
+		}
+		i, err := f.Stat()
+		if err != nil {
+			return
+		}
+		if i.Size() == 0 {
+			os.Remove(f.Name()) //nolint:errcheck,gosec
+		}
+	}()
+
+	launcher, err := beaker.New(ro.launcherOptions(f)...)
+	if err != nil {
+		return nil, fmt.Errorf("creating launcher: %w", err)
+	}
+
+	packs, err := beaker.LaunchPacksFromConfig(ro.workDir, ro.selectRunner(conf))
+	if err != nil {
+		return nil, fmt.Errorf("building launchpacks: %w", err)
+	}
+
+	// Kill the tests when beaker is interrupted
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	att, runErr := launcher.Run(ctx, packs...)
+	if runErr != nil && !errors.Is(runErr, beaker.ErrTimeout) {
+		return nil, runErr
+	}
+	if err := launcher.Write(ctx, att); err != nil {
+		return nil, err
+	}
+	return att, runErr
 }
 
 func addRun(parentCmd *cobra.Command) {
@@ -68,48 +440,12 @@ func addRun(parentCmd *cobra.Command) {
 					opts.workDir = args[0]
 				}
 			}
+			opts.defaultOutputPath(cmd)
 			return nil
 		},
-		RunE: func(cmd *cobra.Command, args []string) (err error) {
-			// Validate the options
-			if err := opts.Validate(); err != nil {
-				return err
-			}
-			cmd.SilenceUsage = true
-
-			f, err := os.Create(opts.outputPath)
-			if err != nil {
-				return fmt.Errorf("opening file: %w", err)
-			}
-
-			defer func() {
-				if err := f.Close(); err != nil {
-					return
-				}
-				i, err := f.Stat()
-				if err != nil {
-					return
-				}
-				if i.Size() == 0 {
-					os.Remove(f.Name()) //nolint:errcheck,gosec
-				}
-			}()
-
-			launcher, err := beaker.New(
-				beaker.WithWriter(f),
-				beaker.WithAttest(opts.attest),
-				beaker.WithWorkDir(opts.workDir),
-			)
-			if err != nil {
-				return fmt.Errorf("creating launcher")
-			}
-
-			pack, err := beaker.LaunchPackFromRepo(opts.workDir)
-			if err != nil {
-				return fmt.Errorf("automatically building launchpack: %w", err)
-			}
-
-			return launcher.Test(context.Background(), pack)
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, err := opts.execute(cmd)
+			return err
 		},
 	}
 	opts.AddFlags(attCmd)
@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/carabiner-dev/beaker/pkg/beaker"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name    string
+		config  string
+		args    []string
+		mustErr bool
+		check   func(t *testing.T, ro *runOptions)
+	}{
+		{
+			name:   "config-values",
+			config: "output: conf.json\ntimeout: 5m\nexitPolicy: strict\nsourceIgnore: [dist]\n",
+			check: func(t *testing.T, ro *runOptions) {
+				t.Helper()
+				require.Equal(t, "conf.json", ro.outputPath)
+				require.Equal(t, 5*time.Minute, ro.timeout)
+				require.Equal(t, "strict", ro.exitPolicy)
+				require.Equal(t, []string{"dist"}, ro.sourceIgnore)
+			},
+		},
+		{
+			name:   "flags-win",
+			config: "output: conf.json\ntimeout: 5m\nexitPolicy: strict\nattest: false\nsourceIgnore: [dist]\n",
+			args:   []string{"--output=flag.json", "--timeout=1m", "--exit-policy=ignore", "--attest=true", "--source-ignore=build"},
+			check: func(t *testing.T, ro *runOptions) {
+				t.Helper()
+				require.Equal(t, "flag.json", ro.outputPath)
+				require.Equal(t, time.Minute, ro.timeout)
+				require.Equal(t, "ignore", ro.exitPolicy)
+				require.True(t, ro.attest)
+				require.Equal(t, []string{"build"}, ro.sourceIgnore)
+			},
+		},
+		{
+			name:   "explicit-false",
+			config: "attest: false\n",
+			check: func(t *testing.T, ro *runOptions) {
+				t.Helper()
+				require.False(t, ro.attest)
+			},
+		},
+		{
+			name:    "invalid",
+			config:  "runners:\n  - name: bazel\n",
+			mustErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, beaker.DefaultConfigFile), []byte(tc.config), 0o600))
+
+			ro := &runOptions{}
+			cmd := &cobra.Command{}
+			ro.AddFlags(cmd)
+			require.NoError(t, cmd.ParseFlags(append([]string{"--dir=" + dir}, tc.args...)))
+
+			conf, err := ro.loadConfig(cmd)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, conf)
+			require.Equal(t, filepath.Join(dir, beaker.DefaultConfigFile), ro.loadedConfig)
+			tc.check(t, ro)
+		})
+	}
+}
+
+func TestLoadConfigMissing(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	// A missing default file is not an error
+	ro := &runOptions{}
+	cmd := &cobra.Command{}
+	ro.AddFlags(cmd)
+	require.NoError(t, cmd.ParseFlags([]string{"--dir=" + dir}))
+	conf, err := ro.loadConfig(cmd)
+	require.NoError(t, err)
+	require.Nil(t, conf)
+
+	// A missing file set in the flags is
+	ro = &runOptions{}
+	cmd = &cobra.Command{}
+	ro.AddFlags(cmd)
+	require.NoError(t, cmd.ParseFlags([]string{"--dir=" + dir, "--config=" + filepath.Join(dir, "missing.yaml")}))
+	_, err = ro.loadConfig(cmd)
+	require.Error(t, err)
+}
+
+func TestExecuteInvalidConfig(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name   string
+		config string
+		args   []string
+		errStr string
+	}{
+		{"archive-mode", "source: archive\n", nil, "requires an archive"},
+		{"remote-and-uri", "remote: upstream\n", []string{"--repo-uri=https://example.com/repo.git"}, "cannot be used together"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, beaker.DefaultConfigFile), []byte(tc.config), 0o600))
+
+			ro := &runOptions{}
+			cmd := &cobra.Command{}
+			ro.AddFlags(cmd)
+			output := filepath.Join(dir, "tests.intoto.json")
+			require.NoError(t, cmd.ParseFlags(append([]string{"--dir=" + dir, "--output=" + output}, tc.args...)))
+
+			_, err := ro.execute(cmd)
+			require.ErrorContains(t, err, tc.errStr)
+			require.NoFileExists(t, output)
+		})
+	}
+}
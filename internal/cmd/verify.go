@@ -0,0 +1,243 @@
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/release-utils/helpers"
+
+	"github.com/carabiner-dev/beaker/pkg/beaker"
+	"github.com/carabiner-dev/beaker/pkg/signing"
+)
+
+// Exit codes of the verify and check subcommands, each outcome has its own
+// code in both. Any other error exits with 1.
+const (
+	exitBadSignature = 2
+	exitWrongSubject = 3
+	exitTestsFailed  = 4
+	exitDirtySubject = 5
+	exitPolicyFailed = 6
+)
+
+// exitError is an error that makes beaker exit with a specific code
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Unwrap() error { return e.err }
+
+type verifyOptions struct {
+	keyPath       string
+	certPath      string
+	commit        string
+	repoPath      string
+	skipSignature bool
+
+	// Locator and version options used to build the subject expected
+	// from the repository, as beaker run does.
+	remote        string
+	repoURI       string
+	tagPattern    string
+	versionFormat string
+}
+
+// Validates the options in context with arguments
+func (vo *verifyOptions) Validate() error {
+	errs := []error{}
+	if vo.keyPath != "" && vo.certPath != "" {
+		errs = append(errs, errors.New("--key and --cert are mutually exclusive"))
+	}
+	if vo.keyPath == "" && vo.certPath == "" && !vo.skipSignature {
+		errs = append(errs, errors.New("a public key (--key) or certificate (--cert) is required to verify the signature, or pass --insecure-skip-signature"))
+	}
+	if vo.commit != "" && vo.repoPath != "" {
+		errs = append(errs, errors.New("--commit and --repo are mutually exclusive"))
+	}
+	if vo.repoPath != "" && !helpers.IsDir(vo.repoPath) {
+		errs = append(errs, fmt.Errorf("repository directory does not exist: %q", vo.repoPath))
+	}
+	if !slices.Contains(beaker.VersionFormats, beaker.VersionFormat(vo.versionFormat)) {
+		errs = append(errs, fmt.Errorf("invalid version format %q", vo.versionFormat))
+	}
+	if vo.remote != "" && vo.repoURI != "" {
+		errs = append(errs, errors.New("--remote and --repo-uri cannot be used together"))
+	}
+	return errors.Join(errs...)
+}
+
+// AddFlags adds the subcommands flags
+func (vo *verifyOptions) AddFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(
+		&vo.keyPath, "key", "k", "", "path to the PEM or GPG public key to verify the signature",
+	)
+	cmd.PersistentFlags().StringVar(
+		&vo.certPath, "cert", "", "path to a PEM certificate, its public key verifies the signature",
+	)
+	cmd.PersistentFlags().BoolVar(
+		&vo.skipSignature, "insecure-skip-signature", false, "accept unsigned attestations and do not check signatures",
+	)
+	cmd.PersistentFlags().StringVar(
+		&vo.commit, "commit", "", "git commit expected in the attestation subject",
+	)
+	cmd.PersistentFlags().StringVar(
+		&vo.repoPath, "repo", "", "path to a local checkout, its HEAD is expected in the attestation subject",
+	)
+	cmd.PersistentFlags().StringVar(
+		&vo.remote, "remote", "", "git remote of --repo expected as the repository URL (defaults to upstream, origin or the first remote by name)",
+	)
+	cmd.PersistentFlags().StringVar(
+		&vo.repoURI, "repo-uri", "", "repository URL expected in the subject instead of the URL of a remote of --repo",
+	)
+	cmd.PersistentFlags().StringVar(
+		&vo.tagPattern, "tag-pattern", "", "glob the git tags must match to compute the expected version, such as 'v*' (defaults to all tags)",
+	)
+	cmd.PersistentFlags().StringVar(
+		&vo.versionFormat, "version-format", string(beaker.VersionFormatSemver),
+		"format of the expected version of commits past the latest tag: semver or describe",
+	)
+}
+
+// verifyOptions returns the options to verify the attestation
+func (vo *verifyOptions) verifyOptions(cmd *cobra.Command) (*beaker.VerifyOptions, error) {
+	verifier, err := loadVerifier(vo.keyPath, vo.certPath)
+	if err != nil {
+		return nil, err
+	}
+	opts := &beaker.VerifyOptions{Verifier: verifier, Commit: vo.commit, SkipSignature: vo.skipSignature}
+
+	if vo.repoPath != "" {
+		if err := vo.loadConfig(cmd); err != nil {
+			return nil, err
+		}
+		opts.Subject, err = beaker.RepoSubject(
+			beaker.WithWorkDir(vo.repoPath),
+			beaker.WithRemote(vo.remote),
+			beaker.WithRepoURI(vo.repoURI),
+			beaker.WithTagPattern(vo.tagPattern),
+			beaker.WithVersionFormat(beaker.VersionFormat(vo.versionFormat)),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("reading repository subject: %w", err)
+		}
+	}
+	return opts, nil
+}
+
+// loadConfig reads the locator and version settings of the configuration
+// file in the repository, if there is one. As in beaker run, they only
+// apply when their flags were not set.
+func (vo *verifyOptions) loadConfig(cmd *cobra.Command) error {
+	path := filepath.Join(vo.repoPath, beaker.DefaultConfigFile)
+	if !helpers.Exists(path) {
+		return nil
+	}
+	conf, err := beaker.LoadConfig(path)
+	if err != nil {
+		return fmt.Errorf("loading configuration: %w", err)
+	}
+	if conf.Remote != "" && !cmd.Flags().Changed("remote") && !cmd.Flags().Changed("repo-uri") {
+		vo.remote = conf.Remote
+	}
+	if conf.RepoURI != "" && !cmd.Flags().Changed("repo-uri") && !cmd.Flags().Changed("remote") {
+		vo.repoURI = conf.RepoURI
+	}
+	if conf.TagPattern != "" && !cmd.Flags().Changed("tag-pattern") {
+		vo.tagPattern = conf.TagPattern
+	}
+	if conf.VersionFormat != "" && !cmd.Flags().Changed("version-format") {
+		vo.versionFormat = string(conf.VersionFormat)
+	}
+	return nil
+}
+
+// loadVerifier returns a verifier for the public key or the certificate.
+// When both paths are empty, signatures are not checked and it returns nil.
+func loadVerifier(keyPath, certPath string) (*signing.Verifier, error) {
+	switch {
+	case keyPath != "":
+		return signing.LoadVerifier(keyPath)
+	case certPath != "":
+		cert, err := signing.LoadCertificate(certPath)
+		if err != nil {
+			return nil, err
+		}
+		return signing.NewCertificateVerifier(cert)
+	default:
+		logrus.Warn("signature verification disabled, unsigned attestations are accepted")
+		return nil, nil
+	}
+}
+
+func addVerify(parentCmd *cobra.Command) {
+	opts := &verifyOptions{}
+	verifyCmd := &cobra.Command{
+		Short: "verifies a test-result attestation produced by beaker",
+		Long: fmt.Sprintf(`verifies a test-result attestation produced by beaker
+
+The attestation can be a signed DSSE envelope or sigstore bundle. A public
+key (--key) or certificate (--cert) is required to check the signature
+unless --insecure-skip-signature is passed, which also accepts unsigned
+in-toto statements. The exit code tells which check failed:
+
+  %d  the signature is invalid or the attestation is not signed
+  %d  no subject matches the expected commit or repository
+  %d  the attested tests did not pass
+  %d  the commit was tested with uncommitted changes
+  1  any other error
+`, exitBadSignature, exitWrongSubject, exitTestsFailed, exitDirtySubject),
+		Use:               "verify attestation.json",
+		Args:              cobra.ExactArgs(1),
+		SilenceUsage:      false,
+		SilenceErrors:     true,
+		PersistentPreRunE: initLogging,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.Validate(); err != nil {
+				return err
+			}
+			cmd.SilenceUsage = true
+
+			vopts, err := opts.verifyOptions(cmd)
+			if err != nil {
+				return err
+			}
+
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				return fmt.Errorf("reading attestation: %w", err)
+			}
+
+			v, err := beaker.Verify(cmd.Context(), data, vopts)
+			switch {
+			case errors.Is(err, beaker.ErrSignature):
+				return &exitError{code: exitBadSignature, err: err}
+			case errors.Is(err, beaker.ErrSubject):
+				return &exitError{code: exitWrongSubject, err: err}
+			case errors.Is(err, beaker.ErrDirtySubject):
+				return &exitError{code: exitDirtySubject, err: err}
+			case errors.Is(err, beaker.ErrTestsFailed):
+				return &exitError{code: exitTestsFailed, err: err}
+			case err != nil:
+				return err
+			}
+
+			fmt.Fprintf(
+				cmd.OutOrStdout(), "attestation verified: %d tests passed (signed: %t)\n",
+				len(v.Predicate.GetPassedTests()), v.Signed,
+			)
+			return nil
+		},
+	}
+	opts.AddFlags(verifyCmd)
+	parentCmd.AddCommand(verifyCmd)
+}
@@ -0,0 +1,45 @@
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyOptionsValidate(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name    string
+		args    []string
+		mustErr bool
+	}{
+		{name: "no-key", args: []string{}, mustErr: true},
+		{name: "commit-no-key", args: []string{"--commit=abc"}, mustErr: true},
+		{name: "key", args: []string{"--key=beaker.pub"}},
+		{name: "cert", args: []string{"--cert=beaker.crt"}},
+		{name: "key-and-cert", args: []string{"--key=beaker.pub", "--cert=beaker.crt"}, mustErr: true},
+		{name: "skip-signature", args: []string{"--insecure-skip-signature"}},
+		{name: "version-format", args: []string{"--insecure-skip-signature", "--version-format=describe"}},
+		{name: "bad-version-format", args: []string{"--insecure-skip-signature", "--version-format=calver"}, mustErr: true},
+		{name: "remote-and-uri", args: []string{"--insecure-skip-signature", "--remote=origin", "--repo-uri=https://example.com/repo.git"}, mustErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			vo := &verifyOptions{}
+			cmd := &cobra.Command{}
+			vo.AddFlags(cmd)
+			require.NoError(t, cmd.ParseFlags(tc.args))
+
+			err := vo.Validate()
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,168 @@
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+// Package dirhash computes deterministic digests of directory trees.
+//
+// The digest is the sha256 of a manifest listing every file in the tree,
+// sorted by path. Each file takes one line:
+//
+//	<kind> <sha256> <path>
+//
+// where kind is "f" for regular files, "x" for executable files and "l"
+// for symbolic links (hashing the link target), and path is relative to
+// the root with forward slashes. Empty directories are not recorded, and
+// ownership and timestamps are ignored so the digest is stable across
+// checkouts and extracted archives of the same sources.
+package dirhash
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultIgnore lists the patterns always excluded from the digest
+var DefaultIgnore = []string{".git"}
+
+// Digest returns the hex encoded sha256 digest of the tree at root. Files
+// and directories matching any of the ignore patterns are excluded, see
+// Match for the pattern syntax.
+func Digest(root string, ignore []string) (string, error) {
+	manifest, err := Manifest(root, ignore)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(manifest))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Manifest returns the manifest of the tree at root that is hashed to
+// compute its digest.
+func Manifest(root string, ignore []string) (string, error) {
+	patterns := append(append([]string{}, DefaultIgnore...), ignore...)
+	for _, p := range patterns {
+		if _, err := path.Match(strings.TrimPrefix(p, "/"), ""); err != nil {
+			return "", fmt.Errorf("invalid ignore pattern %q: %w", p, err)
+		}
+	}
+
+	lines := []string{}
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+
+		if Match(patterns, rel) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		line, err := entry(p, rel, d)
+		if err != nil {
+			return err
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("walking %s: %w", root, err)
+	}
+
+	// WalkDir sorts entries per directory, sort the full paths to not
+	// depend on how separators compare to other characters.
+	sort.Slice(lines, func(i, j int) bool {
+		return lineName(lines[i]) < lineName(lines[j])
+	})
+	return strings.Join(lines, ""), nil
+}
+
+// Match returns true if the relative, slash separated path matches any of
+// the patterns. Patterns without a slash match the name of any file or
+// directory in the tree (like "node_modules" or "*.log"). Patterns with a
+// slash match the path from the root, a leading slash is optional.
+func Match(patterns []string, rel string) bool {
+	for _, p := range patterns {
+		subject := path.Base(rel)
+		if strings.Contains(p, "/") {
+			p = strings.TrimPrefix(p, "/")
+			subject = rel
+		}
+		if ok, err := path.Match(p, subject); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// entry returns the manifest line of a tree entry. Directories and special
+// files have no line.
+func entry(p, rel string, d fs.DirEntry) (string, error) {
+	info, err := d.Info()
+	if err != nil {
+		return "", err
+	}
+
+	var kind string
+	var sum []byte
+	switch mode := info.Mode(); {
+	case mode.IsRegular():
+		kind = "f"
+		if mode&0o111 != 0 {
+			kind = "x"
+		}
+		sum, err = fileSum(p)
+		if err != nil {
+			return "", err
+		}
+	case mode&fs.ModeSymlink != 0:
+		kind = "l"
+		target, err := os.Readlink(p)
+		if err != nil {
+			return "", fmt.Errorf("reading link: %w", err)
+		}
+		s := sha256.Sum256([]byte(filepath.ToSlash(target)))
+		sum = s[:]
+	default:
+		return "", nil
+	}
+	return fmt.Sprintf("%s %s %s\n", kind, hex.EncodeToString(sum), rel), nil
+}
+
+// lineName returns the path of a manifest line
+func lineName(line string) string {
+	// kind (1) + space + 64 hex chars + space
+	return strings.TrimSuffix(line[67:], "\n")
+}
+
+func fileSum(p string) ([]byte, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, fmt.Errorf("opening file: %w", err)
+	}
+	defer f.Close() //nolint:errcheck
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, fmt.Errorf("hashing %s: %w", p, err)
+	}
+	return h.Sum(nil), nil
+}
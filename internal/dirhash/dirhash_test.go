@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package dirhash
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeTree creates the files in a new directory
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0o644))
+	}
+	return dir
+}
+
+func TestDigest(t *testing.T) {
+	t.Parallel()
+	base := map[string]string{
+		"go.mod":       "module example.com/m\n",
+		"main.go":      "package main\n",
+		"pkg/a/a.go":   "package a\n",
+		"pkg/a-b/b.go": "package b\n",
+	}
+	expected, err := Digest(writeTree(t, base), nil)
+	require.NoError(t, err)
+	require.Len(t, expected, 64)
+
+	for _, tc := range []struct {
+		name   string
+		modify func(t *testing.T, dir string)
+		ignore []string
+		same   bool
+	}{
+		{"unchanged", func(*testing.T, string) {}, nil, true},
+		{"git-dir", func(t *testing.T, dir string) {
+			t.Helper()
+			require.NoError(t, os.MkdirAll(filepath.Join(dir, ".git"), 0o755))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("ref"), 0o644))
+		}, nil, true},
+		{"empty-dir", func(t *testing.T, dir string) {
+			t.Helper()
+			require.NoError(t, os.MkdirAll(filepath.Join(dir, "empty"), 0o755))
+		}, nil, true},
+		{"ignored-name", func(t *testing.T, dir string) {
+			t.Helper()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "pkg", "a", "run.log"), []byte("x"), 0o644))
+		}, []string{"*.log"}, true},
+		{"ignored-path", func(t *testing.T, dir string) {
+			t.Helper()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "tests.intoto.json"), []byte("{}"), 0o644))
+		}, []string{"/tests.intoto.json"}, true},
+		{"anchored-path", func(t *testing.T, dir string) {
+			t.Helper()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "pkg", "tests.intoto.json"), []byte("{}"), 0o644))
+		}, []string{"/tests.intoto.json"}, false},
+		{"content", func(t *testing.T, dir string) {
+			t.Helper()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package other\n"), 0o644))
+		}, nil, false},
+		{"rename", func(t *testing.T, dir string) {
+			t.Helper()
+			require.NoError(t, os.Rename(filepath.Join(dir, "main.go"), filepath.Join(dir, "cmd.go")))
+		}, nil, false},
+		{"executable", func(t *testing.T, dir string) {
+			t.Helper()
+			require.NoError(t, os.Chmod(filepath.Join(dir, "main.go"), 0o755))
+		}, nil, false},
+		{"symlink", func(t *testing.T, dir string) {
+			t.Helper()
+			require.NoError(t, os.Symlink("main.go", filepath.Join(dir, "link.go")))
+		}, nil, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			dir := writeTree(t, base)
+			tc.modify(t, dir)
+			digest, err := Digest(dir, tc.ignore)
+			require.NoError(t, err)
+			if tc.same {
+				require.Equal(t, expected, digest)
+			} else {
+				require.NotEqual(t, expected, digest)
+			}
+		})
+	}
+}
+
+func TestManifest(t *testing.T) {
+	t.Parallel()
+	dir := writeTree(t, map[string]string{"a/b": "", "a-b": "", "a.b": ""})
+	manifest, err := Manifest(dir, nil)
+	require.NoError(t, err)
+	empty := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	require.Equal(t, "f "+empty+" a-b\nf "+empty+" a.b\nf "+empty+" a/b\n", manifest)
+
+	_, err = Manifest(dir, []string{"[a"})
+	require.Error(t, err)
+}
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		pattern  string
+		path     string
+		expected bool
+	}{
+		{"node_modules", "node_modules", true},
+		{"node_modules", "web/node_modules", true},
+		{"*.log", "out/test.log", true},
+		{"/out", "out", true},
+		{"/out", "web/out", false},
+		{"web/*.js", "web/app.js", true},
+		{"web/*.js", "web/lib/app.js", false},
+		{"dist", "distribution", false},
+	} {
+		t.Run(tc.pattern+"_"+tc.path, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.expected, Match([]string{tc.pattern}, tc.path))
+		})
+	}
+}
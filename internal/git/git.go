@@ -4,18 +4,54 @@
 package git
 
 import (
-	"bufio"
 	"errors"
 	"fmt"
-	"net/url"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
-	"github.com/go-git/go-git/v5/plumbing/object"
-	"sigs.k8s.io/release-utils/command"
+	"github.com/sirupsen/logrus"
 )
 
+// ErrNotRepository is returned when a path is not in a git repository
+var ErrNotRepository = git.ErrRepositoryNotExists
+
+// errTagNotInHistory is returned when a tag is not reachable from HEAD
+var errTagNotInHistory = errors.New("tag not found in history")
+
+// openRepo opens the repository containing path. The root of the
+// repository is looked up walking up from path, and .git can be a file
+// pointing to the git directory, as in linked worktrees and submodules.
+func openRepo(path string) (*git.Repository, error) {
+	repo, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{
+		DetectDotGit:          true,
+		EnableDotGitCommonDir: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("opening repository: %w", err)
+	}
+	return repo, nil
+}
+
+// RepoRoot returns the root directory of the worktree containing path
+func RepoRoot(path string) (string, error) {
+	repo, err := openRepo(path)
+	if err != nil {
+		return "", err
+	}
+	return worktreeRoot(repo)
+}
+
+func worktreeRoot(repo *git.Repository) (string, error) {
+	wt, err := repo.Worktree()
+	if err != nil {
+		return "", fmt.Errorf("reading worktree: %w", err)
+	}
+	return wt.Filesystem.Root(), nil
+}
+
 func getHeadHash(repo *git.Repository) (string, error) {
 	headRef, err := repo.Head()
 	if err != nil {
@@ -24,11 +60,19 @@ func getHeadHash(repo *git.Repository) (string, error) {
 	return headRef.Hash().String(), nil
 }
 
-// RepoVersion
-func RepoVersion(path string) (tag, commit string, err error) {
-	repo, err := git.PlainOpen(path)
+// RepoVersion returns the version of the commit checked out in the
+// repository at path and its hash. The version is the latest tag reachable
+// from HEAD (see VersionOptions) or, when HEAD is past it, a version
+// synthesized from the tag in the configured format. When no tag matches,
+// the version is empty. Options can be nil.
+func RepoVersion(path string, opts *VersionOptions) (tag, commit string, err error) {
+	if opts == nil {
+		opts = &VersionOptions{}
+	}
+
+	repo, err := openRepo(path)
 	if err != nil {
-		return "", "", fmt.Errorf("opening repository: %w", err)
+		return "", "", err
 	}
 
 	headHash, err := getHeadHash(repo)
@@ -37,7 +81,7 @@ func RepoVersion(path string) (tag, commit string, err error) {
 	}
 
 	// Get the latest tag
-	lastTag, _, err := getLatestTagFromRepository(repo)
+	lastTag, _, err := getLatestTagFromRepository(repo, opts.TagPattern)
 	if err != nil {
 		return "", "", fmt.Errorf("reading latest tag: %w", err)
 	}
@@ -48,7 +92,13 @@ func RepoVersion(path string) (tag, commit string, err error) {
 		return "", headHash, nil
 	}
 
+	// In shallow clones the tag can be past the fetched history, then
+	// the version can't be computed and only the commit is returned.
 	num, err := getCommitsFromTag(repo, lastTag)
+	if errors.Is(err, errTagNotInHistory) {
+		logrus.Debugf("tag %s is not in the fetched history, recording the commit only", lastTag)
+		return "", headHash, nil
+	}
 	if err != nil {
 		return "", "", fmt.Errorf("finding commits from tag: %w", err)
 	}
@@ -58,209 +108,207 @@ func RepoVersion(path string) (tag, commit string, err error) {
 	}
 
 	// If we're not a the tag then we sinthesize the version
-	sep := "-"
-	if strings.Contains(lastTag, "-") {
-		sep = "."
+	version, err := formatVersion(opts.Format, lastTag, num, headHash)
+	if err != nil {
+		return "", "", err
 	}
-	version := lastTag + fmt.Sprintf("%s%d+%s", sep, num, headHash[0:8])
 	return version, headHash, nil
 }
 
-func GetLatestTag(path string) (tag, commit string, err error) {
-	repo, err := git.PlainOpen(path)
+// GetLatestTag returns the latest tag reachable from HEAD matching the
+// pattern and the commit it points to. Both are empty when no tag matches.
+func GetLatestTag(path, pattern string) (tag, commit string, err error) {
+	repo, err := openRepo(path)
 	if err != nil {
-		return "", "", fmt.Errorf("opening repository: %w", err)
+		return "", "", err
 	}
 
-	tag, ocommit, err := getLatestTagFromRepository(repo)
+	tag, ocommit, err := getLatestTagFromRepository(repo, pattern)
 	if err != nil {
 		return "", "", err
 	}
+	if ocommit == nil {
+		return "", "", nil
+	}
 
 	return tag, ocommit.Hash.String(), nil
 }
 
-// getCommitsFromTag returns the number of commits HEAD is from a tag
+// getCommitsFromTag returns the number of commits reachable from HEAD
+// that are not reachable from a tag, as counted by git describe.
 func getCommitsFromTag(repo *git.Repository, tagName string) (int, error) {
 	headRef, err := repo.Head()
 	if err != nil {
 		return 0, fmt.Errorf("reading head from repo: %w", err)
 	}
 
-	tagRef, err := repo.Tag(tagName)
+	tagCommitHash, err := repo.ResolveRevision(plumbing.Revision("refs/tags/" + tagName))
 	if err != nil {
-		return 0, fmt.Errorf("finding tag %q: %w", tagName, err)
+		return 0, fmt.Errorf("resolving tag %q: %w", tagName, err)
 	}
 
-	tagCommitHash, err := repo.ResolveRevision(plumbing.Revision(tagRef.Name().String()))
+	tagAncestors, err := ancestors(repo, *tagCommitHash)
 	if err != nil {
-		return 0, fmt.Errorf("resolving revision")
+		return 0, err
 	}
 
-	tagCommit, err := repo.CommitObject(*tagCommitHash)
+	headAncestors, err := ancestors(repo, headRef.Hash())
 	if err != nil {
-		return 0, fmt.Errorf("finding tagged commit: %w", err)
+		return 0, err
 	}
-
-	// read the commit history backwards
-	cIter, err := repo.Log(&git.LogOptions{From: headRef.Hash()})
-	if err != nil {
-		return 0, fmt.Errorf("creating commit iterator: %w", err)
+	if _, ok := headAncestors[*tagCommitHash]; !ok {
+		return 0, errTagNotInHistory
 	}
 
 	i := 0
-	found := false
-	err = cIter.ForEach(func(c *object.Commit) error {
-		if c.Hash.String() == tagCommit.Hash.String() {
-			found = true
-			return git.ErrTagExists
+	for h := range headAncestors {
+		if _, ok := tagAncestors[h]; !ok {
+			i++
 		}
-		i++
-		return nil
-	})
-	if err != nil && !errors.Is(err, git.ErrTagExists) {
-		return 0, fmt.Errorf("iterating history: %w", err)
-	}
-	if !found {
-		return 0, fmt.Errorf("tag not found in history")
 	}
 	return i, nil
 }
 
-func getLatestTagFromRepository(repo *git.Repository) (string, *object.Commit, error) {
-	tagRefs, err := repo.Tags()
-	if err != nil {
-		return "", nil, err
-	}
-
-	var latestTagCommit *object.Commit
-	var latestTagName string
-
-	err = tagRefs.ForEach(func(tagRef *plumbing.Reference) error {
-		revision := plumbing.Revision(tagRef.Name().String())
-		tagCommitHash, err := repo.ResolveRevision(revision)
-		if err != nil {
-			return err
-		}
-
-		commit, err := repo.CommitObject(*tagCommitHash)
-		if err != nil {
-			return err
-		}
-
-		if latestTagCommit == nil {
-			latestTagCommit = commit
-			latestTagName = tagRef.Name().String()
-		}
-
-		if commit.Committer.When.After(latestTagCommit.Committer.When) {
-			latestTagCommit = commit
-			latestTagName = tagRef.Name().String()
-		}
-
-		return nil
-	})
+// GetRemotes returns the fetch URLs of the remotes of the repository at
+// path, keyed by remote name.
+func GetRemotes(path string) (map[string]string, error) {
+	repo, err := openRepo(path)
 	if err != nil {
-		return "", nil, err
+		return nil, err
 	}
-
-	return strings.TrimPrefix(latestTagName, "refs/tags/"), latestTagCommit, nil
+	return getRemotes(repo)
 }
 
-// TODO(puerco): Port this to pure go before making it public
-func GetRemotes(path string) (map[string]string, error) {
-	remotes := map[string]string{}
-	res, err := command.NewWithWorkDir(path, "git", "remote", "-v").RunSilentSuccessOutput()
+func getRemotes(repo *git.Repository) (map[string]string, error) {
+	rs, err := repo.Remotes()
 	if err != nil {
-		return nil, fmt.Errorf("running git to get remotes: %w", err)
+		return nil, fmt.Errorf("reading remotes: %w", err)
 	}
-	remoteout := res.Output()
 
-	scanner := bufio.NewScanner(strings.NewReader(remoteout))
-	for scanner.Scan() {
-		pts := strings.Fields(scanner.Text())
-		if len(pts) < 2 {
+	remotes := map[string]string{}
+	for _, r := range rs {
+		conf := r.Config()
+		if len(conf.URLs) == 0 {
 			continue
 		}
-		remotes[pts[0]] = pts[1]
+		remotes[conf.Name] = conf.URLs[0]
 	}
 	return remotes, nil
 }
 
-func RepoVCSLocator(path string) (string, error) {
+// RepoVCSLocator returns the VCS locator of the commit checked out in the
+// repository at path, options can be nil.
+func RepoVCSLocator(path string, opts *LocatorOptions) (string, error) {
+	repo, err := openRepo(path)
+	if err != nil {
+		return "", err
+	}
+
 	// PArse some head details:
-	head, err := GetHeadDetails(path)
+	head, err := getHeadDetails(repo)
 	if err != nil {
 		return "", fmt.Errorf("getting head details: %w", err)
 	}
 
 	// Read the remotes from the repo
-	remotes, err := GetRemotes(path)
+	remotes, err := getRemotes(repo)
 	if err != nil {
 		return "", fmt.Errorf("reading remotes: %w", err)
 	}
-	return makeVCSLocator(head, remotes)
+
+	root, err := worktreeRoot(repo)
+	if err != nil {
+		return "", err
+	}
+	return makeVCSLocator(root, head, remotes, opts)
 }
 
 type HeadDetails struct {
 	CommitSHA string
-	Tag       string
+
+	// Tag is the first, in lexical order, of the tags pointing at HEAD.
+	// Empty when HEAD is not tagged.
+	Tag string
 }
 
-// TODO(puerco): Port this to pure go before making it public
+// GetHeadDetails returns the commit checked out in the repository at path
+// and the tag pointing to it, if any. HEAD can be detached.
 func GetHeadDetails(path string) (*HeadDetails, error) {
-	details := &HeadDetails{}
-
-	res, err := command.NewWithWorkDir(path, "git", "rev-parse", "HEAD").RunSilentSuccessOutput()
+	repo, err := openRepo(path)
 	if err != nil {
-		return nil, fmt.Errorf("running git to get revision: %w", err)
+		return nil, err
 	}
-	details.CommitSHA = res.OutputTrimNL()
+	return getHeadDetails(repo)
+}
 
-	res, err = command.NewWithWorkDir(path, "git", "tag", "--points-at", "HEAD").RunSilentSuccessOutput()
+func getHeadDetails(repo *git.Repository) (*HeadDetails, error) {
+	headRef, err := repo.Head()
 	if err != nil {
-		return nil, fmt.Errorf("running git to get revision: %w", err)
+		return nil, fmt.Errorf("reading head from repo: %w", err)
 	}
-	details.Tag = res.OutputTrimNL()
-
-	return details, nil
-}
+	details := &HeadDetails{CommitSHA: headRef.Hash().String()}
 
-func makeVCSLocator(head *HeadDetails, remotes map[string]string) (string, error) {
-	sourceURL := ""
+	tagRefs, err := repo.Tags()
+	if err != nil {
+		return nil, fmt.Errorf("reading tags: %w", err)
+	}
 
-	pref := []string{"upstream", "origin"}
-	for _, k := range pref {
-		if v, ok := remotes[k]; ok {
-			sourceURL = v
-			break
+	tags := []string{}
+	err = tagRefs.ForEach(func(tagRef *plumbing.Reference) error {
+		// Annotated tags are peeled to the commit they point to, tags of
+		// other objects never point at HEAD.
+		hash, err := repo.ResolveRevision(plumbing.Revision(tagRef.Name().String()))
+		if err != nil {
+			return nil //nolint:nilerr
 		}
+		if *hash == headRef.Hash() {
+			tags = append(tags, tagRef.Name().Short())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// If no match, pick the first
-	if sourceURL == "" {
-		for _, v := range remotes {
-			sourceURL = v
-			break
-		}
+	if len(tags) > 0 {
+		slices.Sort(tags)
+		details.Tag = tags[0]
 	}
+	return details, nil
+}
 
-	// If the URL is on ssh, we need to make some changes
-	if strings.Contains(sourceURL, "@") {
-		_, u, _ := strings.Cut(sourceURL, "@")
-		u = strings.Replace(u, ":", "/", 1)
+// makeVCSLocator returns the VCS locator of the HEAD commit. The
+// repository URL is the override in the options or the URL of the
+// selected remote. Repositories without remotes are located by their path
+// on disk.
+func makeVCSLocator(path string, head *HeadDetails, remotes map[string]string, opts *LocatorOptions) (string, error) {
+	if opts == nil {
+		opts = &LocatorOptions{}
+	}
 
-		// if its a github url, remote the .git to make it more compact
-		if strings.HasPrefix(u, "github.com/") {
-			u = strings.TrimSuffix(u, ".git")
+	var locator string
+	var err error
+	switch {
+	case opts.URI != "":
+		locator, err = checkRepoURI(opts.URI)
+	case len(remotes) == 0 && opts.Remote == "":
+		locator, err = fileURL(path, "")
+	default:
+		name, ambiguous, serr := SelectRemote(remotes, opts.Remote)
+		if serr != nil {
+			return "", serr
 		}
-		sourceURL = "ssh://" + u
+		if ambiguous {
+			logrus.Warnf(
+				"repository has several remotes (%s), using %q. Select a remote or set the repository URI to choose it",
+				strings.Join(slices.Sorted(maps.Keys(remotes)), ", "), name,
+			)
+		}
+		locator, err = NormalizeRemote(remotes[name], path)
 	}
-
-	u, err := url.Parse(sourceURL)
 	if err != nil {
-		return "", fmt.Errorf("parsing repo source URL")
+		return "", fmt.Errorf("normalizing repository URL: %w", err)
 	}
 
-	return "git+" + u.String() + "@" + head.CommitSHA, nil
+	return "git+" + locator + "@" + head.CommitSHA, nil
 }
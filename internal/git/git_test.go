@@ -20,7 +20,7 @@ func TestGetLatestTagFromRepository(t *testing.T) {
 		repo, err := git.PlainOpen(tmp)
 		require.NoError(t, err)
 
-		tag, commit, err := getLatestTagFromRepository(repo)
+		tag, commit, err := getLatestTagFromRepository(repo, "")
 		require.NoError(t, err)
 		require.Equal(t, "v1.0.1", tag)
 		require.Equal(t, "3449b21756d4f8981b44f7efd4769c1b61785c29", commit.Hash.String())
@@ -55,10 +55,71 @@ func TestGetLatestTagFromRepository(t *testing.T) {
 	t.Run("version", func(t *testing.T) {
 		t.Parallel()
 
-		version, hash, err := RepoVersion(tmp)
+		version, hash, err := RepoVersion(tmp, nil)
 		require.NoError(t, err)
 
 		require.Equal(t, "v1.0.1-1+2bce182a", version)
 		require.Equal(t, "2bce182a96aa594f7f84858a9de52f7f44fdba17", hash)
 	})
 }
+
+func TestGetRemotes(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name     string
+		fixture  string
+		expected map[string]string
+	}{
+		{"no-remotes", "testdata/tagged-repo.tar.gz", map[string]string{}},
+		{
+			"multiple-remotes", "testdata/remotes-repo.tar.gz", map[string]string{
+				"origin":   "git@github.com:example/fork.git",
+				"upstream": "https://github.com/example/project.git",
+				"mirror":   "https://gitlab.com/example/project.git",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			tmp := t.TempDir()
+			require.NoError(t, tar.Extract(tc.fixture, tmp))
+
+			remotes, err := GetRemotes(tmp)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, remotes)
+		})
+	}
+}
+
+func TestGetHeadDetails(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name     string
+		fixture  string
+		expected *HeadDetails
+	}{
+		{"untagged-head", "testdata/tagged-repo.tar.gz", &HeadDetails{CommitSHA: "2bce182a96aa594f7f84858a9de52f7f44fdba17"}},
+		{"several-tags", "testdata/remotes-repo.tar.gz", &HeadDetails{CommitSHA: "fcddfbaf0896ba1364e000cba019d19152b62e84", Tag: "latest"}},
+		{"detached-annotated", "testdata/detached-repo.tar.gz", &HeadDetails{CommitSHA: "da1b233e0b4996b48daaccb0988c351253ded8b0", Tag: "v1.0.0"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			tmp := t.TempDir()
+			require.NoError(t, tar.Extract(tc.fixture, tmp))
+
+			details, err := GetHeadDetails(tmp)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, details)
+		})
+	}
+}
+
+func TestRepoVCSLocator(t *testing.T) {
+	t.Parallel()
+	tmp := t.TempDir()
+	require.NoError(t, tar.Extract("testdata/remotes-repo.tar.gz", tmp))
+
+	locator, err := RepoVCSLocator(tmp, nil)
+	require.NoError(t, err)
+	require.Equal(t, "git+https://github.com/example/project@fcddfbaf0896ba1364e000cba019d19152b62e84", locator)
+}
@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package git
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/release-utils/tar"
+)
+
+// TestRepoLayouts reads the repository metadata in the layouts found in
+// CI systems and local checkouts:
+//
+//   - shallow-repo: a depth 1 clone of semver-repo with the release-2 and
+//     v1.10.0 tags fetched at depth 1, HEAD detached. The parent of HEAD
+//     (tagged release-2) is present but the rest of the history is not.
+//   - worktree-repo: a clone of semver-repo in main/ and a linked worktree
+//     in linked/ (its .git is a file) checked out at v1.10.0.
+//   - submodule-repo: a superproject with semver-repo as a submodule in
+//     lib/ (its .git is a file) and a docs/ subdirectory.
+func TestRepoLayouts(t *testing.T) {
+	t.Parallel()
+	const (
+		four  = "b8b157291fc3c1bf35934a35b738edc01c1abde1"
+		one   = "da1b233e0b4996b48daaccb0988c351253ded8b0"
+		super = "de0910bff88e78d01d130c68fbb37a03be7fa2e0"
+	)
+
+	for _, tc := range []struct {
+		name    string
+		fixture string
+		dir     string
+		root    string
+		opts    *VersionOptions
+		version string
+		commit  string
+		locator string
+		headTag string
+	}{
+		{
+			name: "shallow", fixture: "shallow-repo.tar.gz",
+			version: "release-2.1+b8b15729", commit: four,
+			locator: "git+https://github.com/example/project@" + four,
+		},
+		{
+			name: "shallow-tag-past-boundary", fixture: "shallow-repo.tar.gz",
+			opts:    &VersionOptions{TagPattern: "v*"},
+			version: "", commit: four,
+			locator: "git+https://github.com/example/project@" + four,
+		},
+		{
+			name: "linked-worktree", fixture: "worktree-repo.tar.gz", dir: "linked", root: "linked",
+			version: "v1.10.0", commit: one, headTag: "v1.10.0",
+			locator: "git+https://github.com/example/project@" + one,
+		},
+		{
+			name: "main-worktree", fixture: "worktree-repo.tar.gz", dir: "main", root: "main",
+			version: "1.10.0-2+b8b15729", commit: four,
+			locator: "git+https://github.com/example/project@" + four,
+		},
+		{
+			name: "submodule", fixture: "submodule-repo.tar.gz", dir: "lib", root: "lib",
+			version: "1.10.0-2+b8b15729", commit: four,
+			locator: "git+https://github.com/example/lib@" + four,
+		},
+		{
+			name: "subdirectory", fixture: "submodule-repo.tar.gz", dir: "docs",
+			version: "v0.3.0", commit: super, headTag: "v0.3.0",
+			locator: "git+https://github.com/example/super@" + super,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			tmp := t.TempDir()
+			require.NoError(t, tar.Extract(filepath.Join("testdata", tc.fixture), tmp))
+			dir := filepath.Join(tmp, tc.dir)
+
+			root, err := RepoRoot(dir)
+			require.NoError(t, err)
+			require.Equal(t, filepath.Join(tmp, tc.root), root)
+
+			version, commit, err := RepoVersion(dir, tc.opts)
+			require.NoError(t, err)
+			require.Equal(t, tc.version, version)
+			require.Equal(t, tc.commit, commit)
+
+			head, err := GetHeadDetails(dir)
+			require.NoError(t, err)
+			require.Equal(t, tc.commit, head.CommitSHA)
+			require.Equal(t, tc.headTag, head.Tag)
+
+			locator, err := RepoVCSLocator(dir, nil)
+			require.NoError(t, err)
+			require.Equal(t, tc.locator, locator)
+
+			status, err := RepoStatus(dir, nil)
+			require.NoError(t, err)
+			require.True(t, status.Clean(), "%+v", status)
+		})
+	}
+}
+
+func TestRepoRootNotRepository(t *testing.T) {
+	t.Parallel()
+	_, err := RepoRoot(t.TempDir())
+	require.ErrorIs(t, err, ErrNotRepository)
+}
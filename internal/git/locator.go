@@ -0,0 +1,162 @@
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package git
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"net/url"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// defaultPorts are the ports dropped from normalized URLs of each scheme
+var defaultPorts = map[string]string{
+	"ssh":   "22",
+	"git":   "9418",
+	"http":  "80",
+	"https": "443",
+}
+
+// preferredRemotes are the remotes used, in order, when none is selected
+var preferredRemotes = []string{"upstream", "origin"}
+
+// LocatorOptions controls the repository URL recorded in the VCS locator
+type LocatorOptions struct {
+	// Remote is the name of the remote to read the URL from. When empty,
+	// the preferred remotes are tried and then the first one by name.
+	Remote string
+
+	// URI overrides the repository URL, the remotes are not read
+	URI string
+}
+
+// scpLike matches remotes in the scp-like syntax: [user@]host:path
+var scpLike = regexp.MustCompile(`^(?:[^@/]+@)?([^:/]+):(.*)$`)
+
+// windowsPath matches local paths starting with a drive letter
+var windowsPath = regexp.MustCompile(`^[a-zA-Z]:[\\/]`)
+
+// NormalizeRemote turns a git remote URL into the URL recorded in the VCS
+// locator. Remotes in the scp-like syntax (git@host:org/repo) become ssh
+// URLs and local paths become file URLs, relative paths are resolved from
+// base. Credentials, query strings and fragments are stripped so they are
+// never attested, hosts are lowercased and default ports dropped. The
+// .git suffix is removed from the path of network remotes so clones of
+// the same repository from any forge get the same locator.
+func NormalizeRemote(remote, base string) (string, error) {
+	remote = strings.TrimSpace(remote)
+	if remote == "" {
+		return "", errors.New("remote URL is empty")
+	}
+
+	switch {
+	case strings.Contains(remote, "://"):
+	case windowsPath.MatchString(remote):
+		return fileURL(remote, base)
+	case scpLike.MatchString(remote):
+		m := scpLike.FindStringSubmatch(remote)
+		remote = "ssh://" + m[1] + "/" + strings.TrimPrefix(m[2], "/")
+	default:
+		return fileURL(remote, base)
+	}
+
+	u, err := url.Parse(remote)
+	if err != nil {
+		return "", fmt.Errorf("parsing remote URL: %w", err)
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	if scheme == "file" {
+		return fileURL(u.Path, base)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("remote URL %q has no host", u.Redacted())
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if port := u.Port(); port != "" && port != defaultPorts[scheme] {
+		host = net.JoinHostPort(host, port)
+	} else if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+
+	p := strings.TrimSuffix(path.Clean("/"+u.Path), "/")
+	p = strings.TrimSuffix(p, ".git")
+	if p == "" {
+		return "", fmt.Errorf("remote URL %q has no repository path", u.Redacted())
+	}
+
+	normalized := &url.URL{Scheme: scheme, Host: host, Path: p}
+	return normalized.String(), nil
+}
+
+// fileURL returns the file URL of a local repository path
+func fileURL(p, base string) (string, error) {
+	if !filepath.IsAbs(p) && !windowsPath.MatchString(p) {
+		abs, err := filepath.Abs(filepath.Join(base, p))
+		if err != nil {
+			return "", fmt.Errorf("resolving local remote path: %w", err)
+		}
+		p = abs
+	}
+	p = strings.ReplaceAll(filepath.ToSlash(filepath.Clean(p)), `\`, "/")
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	u := &url.URL{Scheme: "file", Path: p}
+	return u.String(), nil
+}
+
+// SelectRemote returns the name of the remote to read the repository URL
+// from. When name is set, the remote must exist. Otherwise upstream or
+// origin are used and, when none of them exist, the first remote in
+// lexical order. ambiguous is true when that fallback picked one of
+// several remotes.
+func SelectRemote(remotes map[string]string, name string) (selected string, ambiguous bool, err error) {
+	names := slices.Sorted(maps.Keys(remotes))
+	if name != "" {
+		if _, ok := remotes[name]; !ok {
+			if len(names) == 0 {
+				return "", false, fmt.Errorf("remote %q not found, the repository has no remotes", name)
+			}
+			return "", false, fmt.Errorf("remote %q not found, valid remotes are: %s", name, strings.Join(names, ", "))
+		}
+		return name, false, nil
+	}
+
+	for _, n := range preferredRemotes {
+		if _, ok := remotes[n]; ok {
+			return n, false, nil
+		}
+	}
+
+	if len(names) == 0 {
+		return "", false, errors.New("repository has no remotes")
+	}
+	return names[0], len(names) > 1, nil
+}
+
+// checkRepoURI validates a repository URI set explicitly. It is recorded
+// as is, without the git+ prefix, but it must be an absolute URL and can't
+// include credentials.
+func checkRepoURI(uri string) (string, error) {
+	uri = strings.TrimPrefix(uri, "git+")
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", fmt.Errorf("parsing repository URI: %w", err)
+	}
+	if u.Scheme == "" || (u.Host == "" && u.Scheme != "file") {
+		return "", fmt.Errorf("repository URI %q is not an absolute URL", uri)
+	}
+	if u.User != nil {
+		return "", errors.New("repository URI must not include credentials")
+	}
+	return uri, nil
+}
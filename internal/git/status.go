@@ -0,0 +1,127 @@
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package git
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+
+	"github.com/carabiner-dev/beaker/internal/dirhash"
+)
+
+// Status lists the uncommitted changes in a working tree
+type Status struct {
+	// Modified lists the tracked files changed or deleted in the worktree
+	Modified []string
+
+	// Staged lists the files with changes added to the index
+	Staged []string
+
+	// Untracked lists the files not tracked nor ignored
+	Untracked []string
+
+	// Digest is the hex encoded sha256 digest of the changes, computed from
+	// the status and the current content of each changed file. Empty when
+	// the tree is clean.
+	Digest string
+}
+
+// Clean returns true if the tree has no uncommitted changes
+func (s *Status) Clean() bool {
+	return len(s.Modified) == 0 && len(s.Staged) == 0 && len(s.Untracked) == 0
+}
+
+// RepoStatus returns the uncommitted changes in the repository containing
+// path. Files under path matching the ignore patterns (see dirhash.Match,
+// relative to path) are not considered, such as the files written by
+// beaker itself.
+func RepoStatus(path string, ignore []string) (*Status, error) {
+	repo, err := openRepo(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Status paths are relative to the root, patterns to path
+	base, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("resolving path: %w", err)
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("reading worktree: %w", err)
+	}
+
+	gstatus, err := wt.Status()
+	if err != nil {
+		return nil, fmt.Errorf("reading worktree status: %w", err)
+	}
+
+	status := &Status{Modified: []string{}, Staged: []string{}, Untracked: []string{}}
+	h := sha256.New()
+	for _, name := range slices.Sorted(maps.Keys(gstatus)) {
+		st := gstatus[name]
+		if st.Staging == git.Unmodified && st.Worktree == git.Unmodified {
+			continue
+		}
+		file := filepath.Join(wt.Filesystem.Root(), filepath.FromSlash(name))
+		if rel, err := filepath.Rel(base, file); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			if dirhash.Match(ignore, filepath.ToSlash(rel)) {
+				continue
+			}
+		}
+
+		if st.Worktree == git.Untracked {
+			status.Untracked = append(status.Untracked, name)
+		} else {
+			if st.Staging != git.Unmodified {
+				status.Staged = append(status.Staged, name)
+			}
+			if st.Worktree != git.Unmodified {
+				status.Modified = append(status.Modified, name)
+			}
+		}
+
+		sum, err := worktreeFileDigest(file)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(h, "%c%c %s %s\n", st.Staging, st.Worktree, sum, name)
+	}
+
+	if !status.Clean() {
+		status.Digest = hex.EncodeToString(h.Sum(nil))
+	}
+	return status, nil
+}
+
+// worktreeFileDigest returns the sha256 digest of a file in the worktree
+// or "-" if it was deleted.
+func worktreeFileDigest(path string) (string, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "-", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("opening changed file: %w", err)
+	}
+	defer f.Close() //nolint:errcheck
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("hashing changed file: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
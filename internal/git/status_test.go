@@ -0,0 +1,89 @@
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/release-utils/tar"
+)
+
+// newTestRepo creates a repository with the files committed
+func newTestRepo(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+		_, err := wt.Add(name)
+		require.NoError(t, err)
+	}
+	_, err = wt.Commit("initial commit", &git.CommitOptions{
+		Author: &object.Signature{Name: "beaker", Email: "beaker@example.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+	return dir
+}
+
+func TestRepoStatus(t *testing.T) {
+	t.Parallel()
+	files := map[string]string{"a.txt": "a\n", "b.txt": "b\n", "c.txt": "c\n"}
+
+	t.Run("fixture", func(t *testing.T) {
+		t.Parallel()
+		tmp := t.TempDir()
+		require.NoError(t, tar.Extract("testdata/tagged-repo.tar.gz", tmp))
+		status, err := RepoStatus(tmp, nil)
+		require.NoError(t, err)
+		require.True(t, status.Clean())
+		require.Empty(t, status.Digest)
+	})
+
+	t.Run("clean", func(t *testing.T) {
+		t.Parallel()
+		status, err := RepoStatus(newTestRepo(t, files), nil)
+		require.NoError(t, err)
+		require.True(t, status.Clean())
+		require.Empty(t, status.Digest)
+	})
+
+	t.Run("dirty", func(t *testing.T) {
+		t.Parallel()
+		dir := newTestRepo(t, files)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("changed\n"), 0o644))
+		require.NoError(t, os.Remove(filepath.Join(dir, "b.txt")))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "c.txt"), []byte("staged\n"), 0o644))
+		repo, err := git.PlainOpen(dir)
+		require.NoError(t, err)
+		wt, err := repo.Worktree()
+		require.NoError(t, err)
+		_, err = wt.Add("c.txt")
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "new.txt"), []byte("new\n"), 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "tests.intoto.json"), []byte("{}"), 0o644))
+
+		status, err := RepoStatus(dir, []string{"/tests.intoto.json"})
+		require.NoError(t, err)
+		require.False(t, status.Clean())
+		require.Equal(t, []string{"a.txt", "b.txt"}, status.Modified)
+		require.Equal(t, []string{"c.txt"}, status.Staged)
+		require.Equal(t, []string{"new.txt"}, status.Untracked)
+		require.Len(t, status.Digest, 64)
+
+		// The digest changes with the content of the changed files
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "new.txt"), []byte("newer\n"), 0o644))
+		status2, err := RepoStatus(dir, []string{"/tests.intoto.json"})
+		require.NoError(t, err)
+		require.NotEqual(t, status.Digest, status2.Digest)
+	})
+}
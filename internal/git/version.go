@@ -0,0 +1,204 @@
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package git
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/blang/semver/v4"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// VersionFormat is the format of the versions synthesized for commits
+// past the latest tag.
+type VersionFormat string
+
+const (
+	// VersionFormatSemver appends the number of commits since the tag and
+	// the abbreviated commit hash as build metadata: v1.0.1-3+2bce182a
+	VersionFormatSemver VersionFormat = "semver"
+
+	// VersionFormatDescribe mimics git describe --tags: v1.0.1-3-g2bce182
+	VersionFormatDescribe VersionFormat = "describe"
+)
+
+// VersionFormats lists the valid version formats
+var VersionFormats = []VersionFormat{VersionFormatSemver, VersionFormatDescribe}
+
+// VersionOptions controls how the version of a commit is computed
+type VersionOptions struct {
+	// TagPattern is a glob (as in path.Match) the tags must match to be
+	// considered, such as "v*". Empty matches all tags.
+	TagPattern string
+
+	// Format of the synthesized versions, defaults to VersionFormatSemver
+	Format VersionFormat
+}
+
+// tagCandidate is a tag reachable from HEAD
+type tagCandidate struct {
+	name    string
+	version *semver.Version
+	date    time.Time
+	commit  *object.Commit
+}
+
+// compare orders candidates from the latest to the oldest: tags that are
+// semantic versions go first by precedence, then by date. Names break
+// the remaining ties to keep the order deterministic.
+func (c *tagCandidate) compare(o *tagCandidate) int {
+	switch {
+	case c.version != nil && o.version == nil:
+		return -1
+	case c.version == nil && o.version != nil:
+		return 1
+	case c.version != nil:
+		if cmp := o.version.Compare(*c.version); cmp != 0 {
+			return cmp
+		}
+	}
+	if cmp := o.date.Compare(c.date); cmp != 0 {
+		return cmp
+	}
+	return strings.Compare(c.name, o.name)
+}
+
+// getLatestTagFromRepository returns the latest tag reachable from HEAD
+// matching the pattern and the commit it points to. Tags that are
+// semantic versions are ordered by precedence and preferred over the
+// rest, the date of the tag breaks ties. Annotated tags are dated by
+// their tagger, lightweight ones by the committer of their commit.
+func getLatestTagFromRepository(repo *git.Repository, pattern string) (string, *object.Commit, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return "", nil, fmt.Errorf("invalid tag pattern %q: %w", pattern, err)
+	}
+
+	headRef, err := repo.Head()
+	if err != nil {
+		return "", nil, fmt.Errorf("reading head from repo: %w", err)
+	}
+
+	headAncestors, err := ancestors(repo, headRef.Hash())
+	if err != nil {
+		return "", nil, err
+	}
+
+	tagRefs, err := repo.Tags()
+	if err != nil {
+		return "", nil, err
+	}
+
+	candidates := []*tagCandidate{}
+	err = tagRefs.ForEach(func(tagRef *plumbing.Reference) error {
+		name := tagRef.Name().Short()
+		if pattern != "" {
+			if ok, _ := path.Match(pattern, name); !ok {
+				return nil
+			}
+		}
+
+		c, err := newTagCandidate(repo, tagRef)
+		if err != nil || c == nil {
+			return err
+		}
+		if _, ok := headAncestors[c.commit.Hash]; ok {
+			candidates = append(candidates, c)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	if len(candidates) == 0 {
+		return "", nil, nil
+	}
+	latest := slices.MinFunc(candidates, func(a, b *tagCandidate) int { return a.compare(b) })
+	return latest.name, latest.commit, nil
+}
+
+// newTagCandidate reads the commit and date of a tag. It returns nil for
+// tags pointing to objects other than commits or to commits that were not
+// fetched.
+func newTagCandidate(repo *git.Repository, tagRef *plumbing.Reference) (*tagCandidate, error) {
+	c := &tagCandidate{name: tagRef.Name().Short()}
+	if v, err := semver.ParseTolerant(c.name); err == nil {
+		c.version = &v
+	}
+
+	hash := tagRef.Hash()
+	if tag, err := repo.TagObject(hash); err == nil {
+		c.date = tag.Tagger.When
+		commit, err := tag.Commit()
+		if errors.Is(err, object.ErrUnsupportedObject) || errors.Is(err, plumbing.ErrObjectNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading commit of tag %s: %w", c.name, err)
+		}
+		c.commit = commit
+		return c, nil
+	} else if !errors.Is(err, plumbing.ErrObjectNotFound) {
+		return nil, fmt.Errorf("reading tag %s: %w", c.name, err)
+	}
+
+	commit, err := repo.CommitObject(hash)
+	if errors.Is(err, plumbing.ErrObjectNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading commit of tag %s: %w", c.name, err)
+	}
+	c.commit = commit
+	c.date = commit.Committer.When
+	return c, nil
+}
+
+// ancestors returns the set of commits reachable from a commit. Missing
+// parents, past the boundary of shallow clones, end the walk gracefully.
+func ancestors(repo *git.Repository, from plumbing.Hash) (map[plumbing.Hash]struct{}, error) {
+	seen := map[plumbing.Hash]struct{}{}
+	queue := []plumbing.Hash{from}
+	for len(queue) > 0 {
+		h := queue[0]
+		queue = queue[1:]
+		if _, ok := seen[h]; ok {
+			continue
+		}
+
+		c, err := repo.CommitObject(h)
+		if errors.Is(err, plumbing.ErrObjectNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading commit %s: %w", h, err)
+		}
+		seen[h] = struct{}{}
+		queue = append(queue, c.ParentHashes...)
+	}
+	return seen, nil
+}
+
+// formatVersion synthesizes the version of a commit num commits past tag
+func formatVersion(format VersionFormat, tag string, num int, hash string) (string, error) {
+	switch format {
+	case VersionFormatSemver, "":
+		sep := "-"
+		if strings.Contains(tag, "-") {
+			sep = "."
+		}
+		return tag + fmt.Sprintf("%s%d+%s", sep, num, hash[0:8]), nil
+	case VersionFormatDescribe:
+		return fmt.Sprintf("%s-%d-g%s", tag, num, hash[0:7]), nil
+	default:
+		return "", fmt.Errorf("invalid version format %q", format)
+	}
+}
@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/release-utils/tar"
+)
+
+func TestRepoVersionTags(t *testing.T) {
+	t.Parallel()
+
+	// The fixture history, from HEAD (main) back:
+	//   b8b15729 four
+	//   1420e119 three: release-2
+	//   5cae67c6 two:   v1.9.0, 1.10.0, nightly
+	//   da1b233e one:   v1.10.0 (annotated)
+	// and a side branch from three with v2.0.0 and nightly-2 (annotated),
+	// newer than all the tags in main but not reachable from HEAD.
+	tmp := t.TempDir()
+	require.NoError(t, tar.Extract("testdata/semver-repo.tar.gz", tmp))
+	const head = "b8b157291fc3c1bf35934a35b738edc01c1abde1"
+
+	for _, tc := range []struct {
+		name     string
+		opts     *VersionOptions
+		expected string
+		mustErr  bool
+	}{
+		// v1.10.0 and 1.10.0 have the same precedence, 1.10.0 is newer
+		{"default", nil, "1.10.0-2+b8b15729", false},
+		{"pattern", &VersionOptions{TagPattern: "v*"}, "v1.10.0-3+b8b15729", false},
+		{"describe", &VersionOptions{TagPattern: "v*", Format: VersionFormatDescribe}, "v1.10.0-3-gb8b1572", false},
+		{"not-semver", &VersionOptions{TagPattern: "release-*"}, "release-2.1+b8b15729", false},
+		{"not-semver-describe", &VersionOptions{TagPattern: "release-*", Format: VersionFormatDescribe}, "release-2-1-gb8b1572", false},
+		{"unreachable", &VersionOptions{TagPattern: "nightly*"}, "nightly-2+b8b15729", false},
+		{"no-match", &VersionOptions{TagPattern: "x*"}, "", false},
+		{"bad-pattern", &VersionOptions{TagPattern: "["}, "", true},
+		{"bad-format", &VersionOptions{Format: "calver"}, "", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			version, commit, err := RepoVersion(tmp, tc.opts)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, version)
+			require.Equal(t, head, commit)
+		})
+	}
+}
+
+func TestGetLatestTag(t *testing.T) {
+	t.Parallel()
+	tmp := t.TempDir()
+	require.NoError(t, tar.Extract("testdata/semver-repo.tar.gz", tmp))
+
+	// Annotated tags resolve to the commit they point to
+	tag, commit, err := GetLatestTag(tmp, "v*")
+	require.NoError(t, err)
+	require.Equal(t, "v1.10.0", tag)
+	require.Equal(t, "da1b233e0b4996b48daaccb0988c351253ded8b0", commit)
+
+	tag, commit, err = GetLatestTag(tmp, "none")
+	require.NoError(t, err)
+	require.Empty(t, tag)
+	require.Empty(t, commit)
+}
@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+
+	intoto "github.com/in-toto/attestation/go/v1"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// FileDescriptor returns a descriptor of the file at path with its sha256
+// digest, recorded under name.
+func FileDescriptor(path, name string) (*intoto.ResourceDescriptor, error) {
+	digest, err := fileDigest(path)
+	if err != nil {
+		return nil, err
+	}
+	return &intoto.ResourceDescriptor{
+		Name:   name,
+		Digest: map[string]string{"sha256": digest},
+	}, nil
+}
+
+// ToolDescriptor returns a descriptor of an executable looked up in the
+// PATH. It carries the sha256 digest of the executable and the passed
+// annotations, such as its version.
+func ToolDescriptor(name string, annotations map[string]string) (*intoto.ResourceDescriptor, error) {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return nil, fmt.Errorf("looking up %s: %w", name, err)
+	}
+	digest, err := fileDigest(path)
+	if err != nil {
+		return nil, err
+	}
+
+	rd := &intoto.ResourceDescriptor{
+		Name:   name,
+		Digest: map[string]string{"sha256": digest},
+	}
+	if len(annotations) > 0 {
+		fields := make(map[string]any, len(annotations))
+		for k, v := range annotations {
+			fields[k] = v
+		}
+		rd.Annotations, err = structpb.NewStruct(fields)
+		if err != nil {
+			return nil, fmt.Errorf("building annotations: %w", err)
+		}
+	}
+	return rd, nil
+}
+
+// fileDigest returns the hex encoded sha256 digest of a file
+func fileDigest(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("opening file: %w", err)
+	}
+	defer f.Close() //nolint:errcheck
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("hashing %s: %w", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
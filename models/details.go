@@ -0,0 +1,191 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"time"
+	"unicode/utf8"
+
+	testresult "github.com/in-toto/attestation/go/predicates/test_result/v0"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// DetailsDescriptorName is the name of the configuration descriptor where
+// beaker records the details of a run not covered by the test-result
+// predicate fields.
+const DetailsDescriptorName = "beaker-test-details"
+
+// DefaultOutputLimit is the default maximum size in bytes of the output
+// recorded for each failed entry.
+const DefaultOutputLimit = 4096
+
+// TestDetails holds information about a test run that does not fit in
+// the test-result predicate. It is stored in the annotations of a
+// descriptor in the predicate configuration.
+type TestDetails struct {
+	// Output captures the output associated with failed entries, such as
+	// the compiler errors of a package that did not build. Keyed by the
+	// entry name as recorded in the failed tests.
+	Output map[string]string `json:"output,omitempty"`
+
+	// Skipped lists the tests that did not run because they were skipped.
+	// They are neither passed nor failed tests.
+	Skipped []string `json:"skipped,omitempty"`
+
+	// Todo lists the tests marked as pending work (TAP TODO directive).
+	// Depending on the runner configuration, their failures may not be
+	// counted as failed tests.
+	Todo []string `json:"todo,omitempty"`
+
+	// Durations holds the time each test took to run in seconds, keyed by
+	// the test name, when the runner reports it.
+	Durations map[string]float64 `json:"durations,omitempty"`
+
+	// StartedOn and FinishedOn are the times when the test run started and
+	// finished.
+	StartedOn  time.Time `json:"startedOn,omitzero"`
+	FinishedOn time.Time `json:"finishedOn,omitzero"`
+}
+
+// IsEmpty returns true when no details were recorded
+func (d *TestDetails) IsEmpty() bool {
+	return d == nil || (len(d.Output) == 0 && len(d.Skipped) == 0 && len(d.Todo) == 0 &&
+		len(d.Durations) == 0 && d.StartedOn.IsZero() && d.FinishedOn.IsZero())
+}
+
+// Merge adds the details in other to d. The run times are extended to
+// cover both runs.
+func (d *TestDetails) Merge(other *TestDetails) {
+	if other == nil {
+		return
+	}
+	if len(other.Output) > 0 {
+		if d.Output == nil {
+			d.Output = map[string]string{}
+		}
+		maps.Copy(d.Output, other.Output)
+	}
+	if len(other.Durations) > 0 {
+		if d.Durations == nil {
+			d.Durations = map[string]float64{}
+		}
+		maps.Copy(d.Durations, other.Durations)
+	}
+	d.Skipped = append(d.Skipped, other.Skipped...)
+	d.Todo = append(d.Todo, other.Todo...)
+	if !other.StartedOn.IsZero() && (d.StartedOn.IsZero() || other.StartedOn.Before(d.StartedOn)) {
+		d.StartedOn = other.StartedOn
+	}
+	if other.FinishedOn.After(d.FinishedOn) {
+		d.FinishedOn = other.FinishedOn
+	}
+}
+
+// AddDuration records the time a test took to run
+func (d *TestDetails) AddDuration(test string, seconds float64) {
+	if d.Durations == nil {
+		d.Durations = map[string]float64{}
+	}
+	d.Durations[test] = seconds
+}
+
+// GetTestDetails reads the details recorded in the predicate configuration.
+// If there are none, it returns an empty set of details.
+func GetTestDetails(att *testresult.TestResult) (*TestDetails, error) {
+	details := &TestDetails{}
+	rd := detailsDescriptor(att)
+	if rd == nil || rd.GetAnnotations() == nil {
+		return details, nil
+	}
+
+	data, err := protojson.Marshal(rd.GetAnnotations())
+	if err != nil {
+		return nil, fmt.Errorf("marshaling annotations: %w", err)
+	}
+	if err := json.Unmarshal(data, details); err != nil {
+		return nil, fmt.Errorf("decoding test details: %w", err)
+	}
+	return details, nil
+}
+
+// SetTestDetails records the details in the predicate configuration,
+// replacing any previous ones. Empty details remove the descriptor.
+func SetTestDetails(att *testresult.TestResult, details *TestDetails) error {
+	if att == nil {
+		return nil
+	}
+
+	rd := detailsDescriptor(att)
+	if details.IsEmpty() {
+		if rd != nil {
+			att.Configuration = removeDescriptor(att.GetConfiguration(), rd)
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(details)
+	if err != nil {
+		return fmt.Errorf("encoding test details: %w", err)
+	}
+	fields := map[string]any{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return fmt.Errorf("decoding test details: %w", err)
+	}
+	annotations, err := structpb.NewStruct(fields)
+	if err != nil {
+		return fmt.Errorf("building annotations: %w", err)
+	}
+
+	if rd == nil {
+		rd = &intoto.ResourceDescriptor{Name: DetailsDescriptorName}
+		att.Configuration = append(att.Configuration, rd)
+	}
+	rd.Annotations = annotations
+	return nil
+}
+
+// IsDetailsDescriptor returns true if the descriptor holds test details
+func IsDetailsDescriptor(rd *intoto.ResourceDescriptor) bool {
+	return rd.GetName() == DetailsDescriptorName && rd.GetUri() == "" && len(rd.GetDigest()) == 0
+}
+
+// detailsDescriptor returns the descriptor holding the test details
+func detailsDescriptor(att *testresult.TestResult) *intoto.ResourceDescriptor {
+	for _, rd := range att.GetConfiguration() {
+		if IsDetailsDescriptor(rd) {
+			return rd
+		}
+	}
+	return nil
+}
+
+// removeDescriptor returns the descriptors without rd
+func removeDescriptor(rds []*intoto.ResourceDescriptor, rd *intoto.ResourceDescriptor) []*intoto.ResourceDescriptor {
+	ret := make([]*intoto.ResourceDescriptor, 0, len(rds))
+	for _, d := range rds {
+		if d != rd {
+			ret = append(ret, d)
+		}
+	}
+	return ret
+}
+
+// TruncateOutput limits output to its last limit bytes, the end of the
+// output is kept as it usually holds the failure. A limit of zero or less
+// means no limit.
+func TruncateOutput(output string, limit int) string {
+	if limit <= 0 || len(output) <= limit {
+		return output
+	}
+	start := len(output) - limit
+	for start < len(output) && !utf8.RuneStart(output[start]) {
+		start++
+	}
+	return fmt.Sprintf("[... %d bytes truncated]\n", start) + output[start:]
+}
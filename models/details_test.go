@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	testresult "github.com/in-toto/attestation/go/predicates/test_result/v0"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestDetails(t *testing.T) {
+	t.Parallel()
+	repo := &intoto.ResourceDescriptor{Uri: "git+https://example.com/repo"}
+	att := &testresult.TestResult{Configuration: []*intoto.ResourceDescriptor{repo}}
+
+	details, err := GetTestDetails(att)
+	require.NoError(t, err)
+	require.True(t, details.IsEmpty())
+
+	started := time.Date(2026, 10, 18, 9, 40, 15, 180890837, time.UTC)
+	details.Merge(&TestDetails{
+		Output:     map[string]string{"p [build failed]": "syntax error\n"},
+		Durations:  map[string]float64{"TestA": 0.25},
+		StartedOn:  started,
+		FinishedOn: started.Add(time.Minute),
+	})
+	details.Merge(&TestDetails{StartedOn: started.Add(time.Second), FinishedOn: started.Add(2 * time.Minute)})
+	require.Equal(t, started, details.StartedOn)
+	require.Equal(t, started.Add(2*time.Minute), details.FinishedOn)
+	require.NoError(t, SetTestDetails(att, details))
+	require.Len(t, att.GetConfiguration(), 2)
+	require.NoError(t, att.GetConfiguration()[1].Validate())
+
+	got, err := GetTestDetails(att)
+	require.NoError(t, err)
+	require.Equal(t, details, got)
+
+	// Empty details remove the descriptor
+	require.NoError(t, SetTestDetails(att, &TestDetails{}))
+	require.Equal(t, []*intoto.ResourceDescriptor{repo}, att.GetConfiguration())
+}
+
+func TestTruncateOutput(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name     string
+		output   string
+		limit    int
+		expected string
+	}{
+		{"no-limit", "panic: boom\n", 0, "panic: boom\n"},
+		{"under-limit", "panic: boom\n", 64, "panic: boom\n"},
+		{"truncated", "=== RUN TestA\npanic: boom\n", 12, "[... 14 bytes truncated]\npanic: boom\n"},
+		{"rune-boundary", "héllo", 4, "[... 3 bytes truncated]\nllo"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.expected, TruncateOutput(tc.output, tc.limit))
+		})
+	}
+}
@@ -0,0 +1,32 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path/filepath"
+
+	intoto "github.com/in-toto/attestation/go/v1"
+)
+
+// LogMediaType is the media type of the descriptor referencing the raw
+// output of the test runners.
+const LogMediaType = "text/plain; charset=utf-8; profile=beaker-runner-log"
+
+// NewLogDescriptor returns a descriptor referencing the log file at path
+// with the sha256 digest of its data.
+func NewLogDescriptor(path string, data []byte) *intoto.ResourceDescriptor {
+	sum := sha256.Sum256(data)
+	return &intoto.ResourceDescriptor{
+		Name:      filepath.Base(path),
+		MediaType: LogMediaType,
+		Digest:    map[string]string{"sha256": hex.EncodeToString(sum[:])},
+	}
+}
+
+// IsLogDescriptor returns true if the descriptor references a runner log
+func IsLogDescriptor(rd *intoto.ResourceDescriptor) bool {
+	return rd.GetMediaType() == LogMediaType
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 
 	testresult "github.com/in-toto/attestation/go/predicates/test_result/v0"
+	intoto "github.com/in-toto/attestation/go/v1"
 )
 
 type TestRunner interface {
@@ -16,3 +17,10 @@ type TestRunner interface {
 type ResultsParser interface {
 	ParseResults(context.Context, *testresult.TestResult, []byte) (*testresult.TestResult, error)
 }
+
+// ConfigurationProvider is implemented by runners that describe the
+// environment the tests run in: the toolchain used, the lockfiles of the
+// project, etc. The descriptors are added to the predicate configuration.
+type ConfigurationProvider interface {
+	Configuration(context.Context) ([]*intoto.ResourceDescriptor, error)
+}
@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+package models
+
+import (
+	"fmt"
+
+	intoto "github.com/in-toto/attestation/go/v1"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// SourceAnnotation is the annotation marking the configuration descriptor
+// of sources not tracked in git, it holds the kind of source (SourceKind).
+const SourceAnnotation = "beakerSource"
+
+// SourceKind is the kind of the tested sources when they are not a git
+// repository.
+type SourceKind string
+
+const (
+	// SourceDirectory is a directory tree hashed with the dirhash algorithm
+	SourceDirectory SourceKind = "directory"
+
+	// SourceArchive is an archive, such as a source tarball, hashed as a file
+	SourceArchive SourceKind = "archive"
+)
+
+// NewSourceDescriptor returns a descriptor of tested sources of the given
+// kind with their sha256 digest. Extra annotations, such as the patterns
+// ignored when hashing a directory, are recorded along the source kind.
+func NewSourceDescriptor(kind SourceKind, name, digest string, annotations map[string]any) (*intoto.ResourceDescriptor, error) {
+	fields := map[string]any{SourceAnnotation: string(kind)}
+	for k, v := range annotations {
+		fields[k] = v
+	}
+	st, err := structpb.NewStruct(fields)
+	if err != nil {
+		return nil, fmt.Errorf("building annotations: %w", err)
+	}
+	return &intoto.ResourceDescriptor{
+		Name:        name,
+		Digest:      map[string]string{"sha256": digest},
+		Annotations: st,
+	}, nil
+}
+
+// IsSourceDescriptor returns true if the descriptor references sources
+// not tracked in git.
+func IsSourceDescriptor(rd *intoto.ResourceDescriptor) bool {
+	_, ok := rd.GetAnnotations().GetFields()[SourceAnnotation]
+	return ok
+}
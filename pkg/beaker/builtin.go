@@ -0,0 +1,170 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+package beaker
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"sigs.k8s.io/release-utils/helpers"
+
+	"github.com/carabiner-dev/beaker/models"
+	"github.com/carabiner-dev/beaker/pkg/parsers/junit"
+	"github.com/carabiner-dev/beaker/pkg/runners/cargo"
+	"github.com/carabiner-dev/beaker/pkg/runners/golang"
+	"github.com/carabiner-dev/beaker/pkg/runners/gradle"
+	"github.com/carabiner-dev/beaker/pkg/runners/maven"
+	"github.com/carabiner-dev/beaker/pkg/runners/npm"
+	"github.com/carabiner-dev/beaker/pkg/runners/pytest"
+	"github.com/carabiner-dev/beaker/pkg/runners/shell"
+)
+
+const (
+	runnerCargo  = "cargo"
+	runnerGolang = "golang"
+	runnerGradle = "gradle"
+	runnerMaven  = "maven"
+	runnerNpm    = "npm"
+	runnerPytest = "pytest"
+	runnerShell  = "shell"
+
+	parserJUnit = "junit"
+)
+
+// Register the runners and parsers that ship with beaker
+func init() {
+	mustRegisterRunner(RunnerDefinition{
+		Name:   runnerGolang,
+		Detect: fileDetector("go.mod"),
+		New: func(path string, conf *RunnerConfig) (*LaunchPack, error) {
+			gorunner, err := golang.New(
+				golang.WithWorkDir(path),
+				golang.WithShellOptions(conf.ShellOptions()...),
+				golang.WithOutputLimit(conf.GetOutputLimit()),
+			)
+			if err != nil {
+				return nil, fmt.Errorf("initializing go launchpack: %w", err)
+			}
+			return &LaunchPack{Runner: gorunner, Parser: gorunner}, nil
+		},
+	})
+
+	mustRegisterRunner(RunnerDefinition{
+		Name:   runnerNpm,
+		Detect: fileDetector("package.json"),
+		New: func(path string, conf *RunnerConfig) (*LaunchPack, error) {
+			npmrunner, err := npm.New(
+				npm.WithWorkDir(path),
+				npm.WithShellOptions(conf.ShellOptions()...),
+				npm.WithTodoFailures(conf.TodoFailures),
+				npm.WithOutputLimit(conf.GetOutputLimit()),
+			)
+			if err != nil {
+				return nil, fmt.Errorf("initializing npm launchpack: %w", err)
+			}
+			return &LaunchPack{Runner: npmrunner, Parser: npmrunner}, nil
+		},
+	})
+
+	mustRegisterRunner(RunnerDefinition{
+		Name:   runnerPytest,
+		Detect: fileDetector(pytest.ProjectFiles...),
+		New: func(path string, conf *RunnerConfig) (*LaunchPack, error) {
+			pyrunner, err := pytest.New(pytest.WithWorkDir(path), pytest.WithShellOptions(conf.ShellOptions()...))
+			if err != nil {
+				return nil, fmt.Errorf("initializing pytest launchpack: %w", err)
+			}
+			return &LaunchPack{Runner: pyrunner, Parser: pyrunner}, nil
+		},
+	})
+
+	mustRegisterRunner(RunnerDefinition{
+		Name:   runnerCargo,
+		Detect: fileDetector("Cargo.toml"),
+		New: func(path string, conf *RunnerConfig) (*LaunchPack, error) {
+			opts := []cargo.OptFn{cargo.WithWorkDir(path), cargo.WithShellOptions(conf.ShellOptions()...)}
+			if conf.LibtestJSON != nil {
+				opts = append(opts, cargo.WithJSON(*conf.LibtestJSON))
+			}
+			cargorunner, err := cargo.New(opts...)
+			if err != nil {
+				return nil, fmt.Errorf("initializing cargo launchpack: %w", err)
+			}
+			return &LaunchPack{Runner: cargorunner, Parser: cargorunner}, nil
+		},
+	})
+
+	mustRegisterRunner(RunnerDefinition{
+		Name:   runnerMaven,
+		Detect: fileDetector("pom.xml"),
+		New: func(path string, conf *RunnerConfig) (*LaunchPack, error) {
+			mvnrunner, err := maven.New(maven.WithWorkDir(path), maven.WithShellOptions(conf.ShellOptions()...))
+			if err != nil {
+				return nil, fmt.Errorf("initializing maven launchpack: %w", err)
+			}
+			return &LaunchPack{Runner: mvnrunner, Parser: mvnrunner}, nil
+		},
+	})
+
+	mustRegisterRunner(RunnerDefinition{
+		Name:   runnerGradle,
+		Detect: fileDetector(gradle.ProjectFiles...),
+		New: func(path string, conf *RunnerConfig) (*LaunchPack, error) {
+			gradlerunner, err := gradle.New(gradle.WithWorkDir(path), gradle.WithShellOptions(conf.ShellOptions()...))
+			if err != nil {
+				return nil, fmt.Errorf("initializing gradle launchpack: %w", err)
+			}
+			return &LaunchPack{Runner: gradlerunner, Parser: gradlerunner}, nil
+		},
+	})
+
+	// The shell runner is never detected, it runs the configured command
+	// and needs a parser to be paired with it.
+	mustRegisterRunner(RunnerDefinition{
+		Name: runnerShell,
+		New: func(path string, conf *RunnerConfig) (*LaunchPack, error) {
+			shellrunner, err := shell.New(append([]shell.OptFn{shell.WithWorkDir(path)}, conf.ShellOptions()...)...)
+			if err != nil {
+				return nil, fmt.Errorf("initializing shell runner: %w", err)
+			}
+			return &LaunchPack{Runner: shellrunner}, nil
+		},
+	})
+
+	mustRegisterParser(runnerGolang, func(path string, conf *RunnerConfig) (models.ResultsParser, error) {
+		return golang.New(golang.WithWorkDir(path), golang.WithOutputLimit(conf.GetOutputLimit()))
+	})
+	mustRegisterParser(runnerNpm, func(path string, conf *RunnerConfig) (models.ResultsParser, error) {
+		return npm.New(
+			npm.WithWorkDir(path),
+			npm.WithTodoFailures(conf.TodoFailures),
+			npm.WithOutputLimit(conf.GetOutputLimit()),
+		)
+	})
+	mustRegisterParser(runnerCargo, func(path string, _ *RunnerConfig) (models.ResultsParser, error) {
+		return cargo.New(cargo.WithWorkDir(path))
+	})
+	mustRegisterParser(runnerPytest, func(path string, _ *RunnerConfig) (models.ResultsParser, error) {
+		return pytest.New(pytest.WithWorkDir(path))
+	})
+
+	// The JUnit parser reads the files listed in the runner reports or,
+	// when none are set, the runner output.
+	mustRegisterParser(parserJUnit, func(path string, conf *RunnerConfig) (models.ResultsParser, error) {
+		return junit.New(junit.WithWorkDir(path), junit.WithReports(conf.Reports...))
+	})
+}
+
+// fileDetector returns a detection function that checks for the
+// existence of any of the passed files at the root of the codebase.
+func fileDetector(names ...string) DetectFunc {
+	return func(path string) bool {
+		for _, name := range names {
+			if helpers.Exists(filepath.Join(path, name)) {
+				return true
+			}
+		}
+		return false
+	}
+}
@@ -0,0 +1,238 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+package beaker
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/carabiner-dev/beaker/models"
+	"github.com/carabiner-dev/beaker/pkg/runners/shell"
+)
+
+// DefaultConfigFile is the name of the configuration file beaker looks for
+// at the root of the codebase.
+const DefaultConfigFile = ".beaker.yaml"
+
+// Config is the schema of the beaker configuration file.
+type Config struct {
+	// Output is the path where the attestation or predicate is written.
+	Output string `yaml:"output"`
+
+	// Attest controls if the full statement is written instead of only
+	// the predicate. When unset, the launcher default is used.
+	Attest *bool `yaml:"attest"`
+
+	// Timeout limits the time the tests can run, e.g. "15m".
+	Timeout time.Duration `yaml:"timeout"`
+
+	// ExitPolicy sets how the exit status of the runners is reconciled
+	// with their parsed results (ignore, error, strict).
+	ExitPolicy ExitPolicy `yaml:"exitPolicy"`
+
+	// Log is the path where the raw output of the runners is written. The
+	// attestation references it by its digest.
+	Log string `yaml:"log"`
+
+	// Source selects how the tested sources are identified in the subject
+	// (git, dir, archive).
+	Source SourceMode `yaml:"source"`
+
+	// SourceIgnore lists patterns of files that are not part of the
+	// sources, excluded from the codebase digest and the dirty tree check.
+	SourceIgnore []string `yaml:"sourceIgnore"`
+
+	// SourceArchive is the path of the archive hashed in archive source
+	// mode.
+	SourceArchive string `yaml:"sourceArchive"`
+
+	// Remote is the git remote recorded as the repository URL
+	Remote string `yaml:"remote"`
+
+	// RepoURI overrides the repository URL recorded in the attestation
+	RepoURI string `yaml:"repoURI"`
+
+	// TagPattern is the glob the git tags must match to compute the
+	// version of the sources, such as "v*".
+	TagPattern string `yaml:"tagPattern"`
+
+	// VersionFormat is the format of the version of commits past the
+	// latest tag (semver, describe).
+	VersionFormat VersionFormat `yaml:"versionFormat"`
+
+	// RequireClean refuses to run the tests when the git working tree has
+	// uncommitted changes.
+	RequireClean bool `yaml:"requireClean"`
+
+	// Runners lists the test runners to execute.
+	Runners []RunnerConfig `yaml:"runners"`
+}
+
+// RunnerConfig configures a runner and the parser that reads its output.
+type RunnerConfig struct {
+	// Name selects the runner from the registry (golang, npm, shell...).
+	Name string `yaml:"name"`
+
+	// Command overrides the executable invoked by the runner.
+	Command string `yaml:"command"`
+
+	// Args overrides the arguments passed to the command.
+	Args []string `yaml:"args"`
+
+	// Env holds extra environment variables for the command.
+	Env map[string]string `yaml:"env"`
+
+	// Parser selects the results parser. Defaults to the parser of the
+	// runner itself. Required for the shell runner.
+	Parser string `yaml:"parser"`
+
+	// Reports are glob patterns of the report files read by parsers that
+	// don't parse the runner output, such as junit. Patterns are relative
+	// to the codebase and support ** to match any number of directories.
+	Reports []string `yaml:"reports"`
+
+	// TodoFailures counts failing tests marked as TODO as failed tests in
+	// parsers that support the directive (npm). By default they are only
+	// recorded as TODO.
+	TodoFailures bool `yaml:"todoFailures"`
+
+	// LibtestJSON forces (true) or disables (false) the libtest JSON output
+	// of the cargo tests. When unset, it is used if the toolchain accepts
+	// unstable options and the human readable output is parsed otherwise.
+	LibtestJSON *bool `yaml:"libtestJSON"`
+
+	// OutputLimit is the maximum size in bytes of the output recorded for
+	// each failed test by the parsers that capture it (golang, npm). Zero
+	// records the full output. When unset, the parser default is used.
+	OutputLimit *int `yaml:"outputLimit"`
+}
+
+// LoadConfig reads and validates a configuration file.
+func LoadConfig(path string) (*Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening config file: %w", err)
+	}
+	defer f.Close() //nolint:errcheck
+
+	conf, err := ParseConfig(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return conf, nil
+}
+
+// ParseConfig parses configuration data from a reader. Unknown keys are
+// rejected and the resulting configuration is validated.
+func ParseConfig(r io.Reader) (*Config, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading config data: %w", err)
+	}
+
+	conf := &Config{}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(conf); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parsing config: %w", err)
+	}
+
+	if err := conf.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	return conf, nil
+}
+
+// Validate checks the configuration for errors
+func (c *Config) Validate() error {
+	errs := []error{}
+	if c.Timeout < 0 {
+		errs = append(errs, fmt.Errorf("invalid timeout %s", c.Timeout))
+	}
+	if c.ExitPolicy != "" && !slices.Contains(ExitPolicies, c.ExitPolicy) {
+		errs = append(errs, fmt.Errorf("invalid exit policy %q", c.ExitPolicy))
+	}
+	if c.Remote != "" && c.RepoURI != "" {
+		errs = append(errs, errors.New("remote and repoURI cannot be set together"))
+	}
+	if c.VersionFormat != "" && !slices.Contains(VersionFormats, c.VersionFormat) {
+		errs = append(errs, fmt.Errorf("invalid version format %q", c.VersionFormat))
+	}
+	if c.Source != "" && !slices.Contains(SourceModes, c.Source) {
+		errs = append(errs, fmt.Errorf("invalid source mode %q", c.Source))
+	}
+	for i := range c.Runners {
+		if err := c.Runners[i].Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("runner #%d: %w", i+1, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Validate checks a runner entry for errors
+func (rc *RunnerConfig) Validate() error {
+	errs := []error{}
+	if rc.Name == "" {
+		errs = append(errs, errors.New("runner name not set"))
+	} else if _, ok := getRunner(rc.Name); !ok {
+		errs = append(errs, fmt.Errorf(
+			"unknown runner %q, valid runners are: %s", rc.Name, strings.Join(Runners(), ", "),
+		))
+	}
+
+	if _, ok := getParser(rc.Parser); rc.Parser != "" && !ok {
+		errs = append(errs, fmt.Errorf(
+			"unknown parser %q, valid parsers are: %s", rc.Parser, strings.Join(Parsers(), ", "),
+		))
+	}
+
+	if rc.OutputLimit != nil && *rc.OutputLimit < 0 {
+		errs = append(errs, fmt.Errorf("invalid output limit %d", *rc.OutputLimit))
+	}
+
+	if rc.Name == runnerShell {
+		if rc.Command == "" {
+			errs = append(errs, errors.New("shell runner requires a command"))
+		}
+		if rc.Parser == "" {
+			errs = append(errs, errors.New("shell runner requires a parser"))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// GetOutputLimit returns the output limit of the runner, falling back to
+// the default when it is not set.
+func (rc *RunnerConfig) GetOutputLimit() int {
+	if rc.OutputLimit == nil {
+		return models.DefaultOutputLimit
+	}
+	return *rc.OutputLimit
+}
+
+// ShellOptions returns the shell runner options that override the
+// runner defaults.
+func (rc *RunnerConfig) ShellOptions() []shell.OptFn {
+	funcs := []shell.OptFn{}
+	if rc.Command != "" {
+		funcs = append(funcs, shell.WithCommand(rc.Command))
+	}
+	if rc.Args != nil {
+		funcs = append(funcs, shell.WithArguments(rc.Args))
+	}
+	// Variables are added to the environment set by the runner
+	for _, name := range slices.Sorted(maps.Keys(rc.Env)) {
+		funcs = append(funcs, shell.WithEnvVar(name, rc.Env[name]))
+	}
+	return funcs
+}
@@ -0,0 +1,151 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+package beaker
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/carabiner-dev/beaker/models"
+	"github.com/carabiner-dev/beaker/pkg/runners/shell"
+)
+
+func TestParseConfig(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name    string
+		data    string
+		mustErr bool
+		check   func(t *testing.T, conf *Config)
+	}{
+		{
+			name: "empty",
+			data: "",
+			check: func(t *testing.T, conf *Config) {
+				t.Helper()
+				require.Empty(t, conf.Runners)
+			},
+		},
+		{
+			name: "full",
+			data: `
+output: results.json
+attest: false
+timeout: 15m
+exitPolicy: strict
+source: dir
+runners:
+  - name: golang
+    args: ["test", "-json", "./pkg/..."]
+    env:
+      GOFLAGS: -mod=mod
+  - name: shell
+    command: ./run-tests.sh
+    parser: junit
+    reports: ["build/**/*.xml"]
+`,
+			check: func(t *testing.T, conf *Config) {
+				t.Helper()
+				require.Equal(t, "results.json", conf.Output)
+				require.NotNil(t, conf.Attest)
+				require.False(t, *conf.Attest)
+				require.Equal(t, 15*time.Minute, conf.Timeout)
+				require.Equal(t, ExitPolicyStrict, conf.ExitPolicy)
+				require.Equal(t, SourceDirectory, conf.Source)
+				require.Len(t, conf.Runners, 2)
+				require.Equal(t, map[string]string{"GOFLAGS": "-mod=mod"}, conf.Runners[0].Env)
+				require.Equal(t, []string{"build/**/*.xml"}, conf.Runners[1].Reports)
+			},
+		},
+		{name: "unknown-key", data: "outptu: results.json\n", mustErr: true},
+		{name: "unknown-runner-key", data: "runners:\n  - name: golang\n    arguments: [test]\n", mustErr: true},
+		{name: "unknown-runner", data: "runners:\n  - name: bazel\n", mustErr: true},
+		{name: "no-runner-name", data: "runners:\n  - command: make\n", mustErr: true},
+		{name: "unknown-parser", data: "runners:\n  - name: golang\n    parser: tap\n", mustErr: true},
+		{name: "shell-no-command", data: "runners:\n  - name: shell\n    parser: junit\n", mustErr: true},
+		{name: "shell-no-parser", data: "runners:\n  - name: shell\n    command: make test\n", mustErr: true},
+		{name: "negative-timeout", data: "timeout: -1m\n", mustErr: true},
+		{name: "negative-output-limit", data: "runners:\n  - name: golang\n    outputLimit: -1\n", mustErr: true},
+		{name: "exit-policy", data: "exitPolicy: lenient\n", mustErr: true},
+		{name: "source-mode", data: "source: tarball\n", mustErr: true},
+		{name: "version-format", data: "versionFormat: calver\n", mustErr: true},
+		{name: "remote-and-uri", data: "remote: upstream\nrepoURI: https://example.com/repo\n", mustErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			conf, err := ParseConfig(strings.NewReader(tc.data))
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if tc.check != nil {
+				tc.check(t, conf)
+			}
+		})
+	}
+}
+
+func TestRunnerConfigShellOptions(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name     string
+		conf     RunnerConfig
+		expected shell.Options
+	}{
+		{
+			name:     "defaults",
+			conf:     RunnerConfig{Name: runnerGolang},
+			expected: shell.Options{Command: "go", Args: []string{"test"}, Env: map[string]string{"GOFLAGS": "-mod=mod"}},
+		},
+		{
+			name: "overrides",
+			conf: RunnerConfig{
+				Name:    runnerGolang,
+				Command: "gotestsum",
+				Args:    []string{"--", "./..."},
+				Env:     map[string]string{"CGO_ENABLED": "0", "GOFLAGS": "-mod=vendor"},
+			},
+			expected: shell.Options{
+				Command: "gotestsum",
+				Args:    []string{"--", "./..."},
+				Env:     map[string]string{"CGO_ENABLED": "0", "GOFLAGS": "-mod=vendor"},
+			},
+		},
+		{
+			name: "merge-env",
+			conf: RunnerConfig{Name: runnerGolang, Env: map[string]string{"CGO_ENABLED": "0"}},
+			expected: shell.Options{
+				Command: "go",
+				Args:    []string{"test"},
+				Env:     map[string]string{"CGO_ENABLED": "0", "GOFLAGS": "-mod=mod"},
+			},
+		},
+		{
+			name:     "empty-args",
+			conf:     RunnerConfig{Name: runnerGolang, Args: []string{}},
+			expected: shell.Options{Command: "go", Args: []string{}, Env: map[string]string{"GOFLAGS": "-mod=mod"}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			// The configured environment is merged into the runner's
+			opts := shell.Options{Command: "go", Args: []string{"test"}, Env: map[string]string{"GOFLAGS": "-mod=mod"}}
+			for _, f := range tc.conf.ShellOptions() {
+				require.NoError(t, f(&opts))
+			}
+			require.Equal(t, tc.expected, opts)
+		})
+	}
+}
+
+func TestRunnerConfigOutputLimit(t *testing.T) {
+	t.Parallel()
+	limit := 0
+	require.Equal(t, models.DefaultOutputLimit, (&RunnerConfig{}).GetOutputLimit())
+	require.Equal(t, 0, (&RunnerConfig{OutputLimit: &limit}).GetOutputLimit())
+}
@@ -5,60 +5,197 @@ package beaker
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"path/filepath"
+	"slices"
 
 	v0 "github.com/in-toto/attestation/go/predicates/test_result/v0"
 	v1 "github.com/in-toto/attestation/go/v1"
-	"sigs.k8s.io/release-utils/helpers"
+	"google.golang.org/protobuf/types/known/structpb"
 
+	"github.com/carabiner-dev/beaker/internal/dirhash"
 	"github.com/carabiner-dev/beaker/internal/git"
+	"github.com/carabiner-dev/beaker/models"
 )
 
 type launcherImplementation interface {
 	InitAttestation(context.Context, *Options) (*v0.TestResult, error)
-	RunLaunchPack(context.Context, *Options, *LaunchPack) ([]byte, error)
+	RunLaunchPack(context.Context, *Options, *LaunchPack) ([]byte, bool, error)
 }
 
 type defaultLauncherImplementation struct{}
 
-func (dli *defaultLauncherImplementation) RunLaunchPack(ctx context.Context, opts *Options, pack *LaunchPack) ([]byte, error) {
-	output, _, err := pack.Runner.Run(ctx)
+// RunLaunchPack executes the pack runner and returns its output and exit
+// status. The output captured so far is returned along runner errors.
+func (dli *defaultLauncherImplementation) RunLaunchPack(ctx context.Context, opts *Options, pack *LaunchPack) ([]byte, bool, error) {
+	output, pass, err := pack.Runner.Run(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("runner error: %w", err)
+		return output, false, fmt.Errorf("runner error: %w", err)
 	}
-	return output, nil
+	return output, pass, nil
 }
 
+// InitAttestation returns the test results initialized with the
+// descriptor of the tested sources, read according to the source mode.
 func (dli *defaultLauncherImplementation) InitAttestation(_ context.Context, opts *Options) (*v0.TestResult, error) {
-	if !helpers.Exists(filepath.Join(opts.WorkDir, ".git")) {
-		return nil, nil
+	switch opts.Source {
+	case SourceDirectory:
+		return directoryAttestation(opts)
+	case SourceArchive:
+		return archiveAttestation(opts)
 	}
 
-	locator, err := git.RepoVCSLocator(opts.WorkDir)
+	// The working directory can be anywhere in the repository
+	if _, err := git.RepoRoot(opts.WorkDir); err != nil {
+		if errors.Is(err, git.ErrNotRepository) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("finding repository root: %w", err)
+	}
+
+	rd, err := repoDescriptor(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := git.RepoStatus(opts.WorkDir, opts.SourceIgnore)
+	if err != nil {
+		return nil, fmt.Errorf("reading working tree status: %w", err)
+	}
+	if !status.Clean() && opts.RequireClean {
+		return nil, fmt.Errorf(
+			"%w: %d modified, %d staged and %d untracked files",
+			ErrDirtyTree, len(status.Modified), len(status.Staged), len(status.Untracked),
+		)
+	}
+
+	if !status.Clean() {
+		if err := markDirty(rd, status); err != nil {
+			return nil, err
+		}
+	}
+
+	att := &v0.TestResult{
+		Configuration: []*v1.ResourceDescriptor{rd},
+		// Url: "",
+	}
+	return att, nil
+}
+
+// RepoSubject returns the subject beaker records when attesting the git
+// repository in the working directory, built with the locator and version
+// options. It does not read the working tree status, so the subject is
+// never marked dirty.
+func RepoSubject(funcs ...OptFn) (*v1.ResourceDescriptor, error) {
+	opts := Options{WorkDir: ".", VersionFormat: VersionFormatSemver}
+	for _, f := range funcs {
+		if err := f(&opts); err != nil {
+			return nil, err
+		}
+	}
+	return repoDescriptor(&opts)
+}
+
+// repoDescriptor returns the descriptor of the commit checked out in the
+// working directory: its version, VCS locator and commit digests.
+func repoDescriptor(opts *Options) (*v1.ResourceDescriptor, error) {
+	locator, err := git.RepoVCSLocator(opts.WorkDir, &git.LocatorOptions{
+		Remote: opts.Remote,
+		URI:    opts.RepoURI,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("reading VCS locator: %w", err)
 	}
 
 	// Get the repo version
-	tagPlus, commit, err := git.RepoVersion(opts.WorkDir)
+	tagPlus, commit, err := git.RepoVersion(opts.WorkDir, &git.VersionOptions{
+		TagPattern: opts.TagPattern,
+		Format:     opts.VersionFormat,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("computing git commit: %w", err)
 	}
 
-	// Build the attestation
-	att := &v0.TestResult{
-		Configuration: []*v1.ResourceDescriptor{
-			{
-				Name: tagPlus,
-				Uri:  locator,
-				Digest: map[string]string{
-					"sha1":      commit,
-					"gitCommit": commit,
-				},
-			},
+	return &v1.ResourceDescriptor{
+		Name: tagPlus,
+		Uri:  locator,
+		Digest: map[string]string{
+			"sha1":      commit,
+			"gitCommit": commit,
 		},
-		// Url: "",
+	}, nil
+}
+
+// markDirty records the uncommitted changes of the working tree in the
+// repository descriptor: the version gets a -dirty suffix and the changed
+// files and the digest of the changes are added to its annotations.
+func markDirty(rd *v1.ResourceDescriptor, status *git.Status) error {
+	name := rd.GetName()
+	if name == "" {
+		name = rd.GetDigest()["gitCommit"][0:8]
 	}
-	return att, nil
+	rd.Name = name + "-dirty"
+
+	annotations, err := structpb.NewStruct(map[string]any{
+		"dirty":         true,
+		"modified":      stringList(status.Modified),
+		"staged":        stringList(status.Staged),
+		"untracked":     stringList(status.Untracked),
+		"changesSha256": status.Digest,
+	})
+	if err != nil {
+		return fmt.Errorf("building dirty tree annotations: %w", err)
+	}
+	rd.Annotations = annotations
+	return nil
+}
+
+// stringList converts a string slice to a list accepted by structpb
+func stringList(s []string) []any {
+	l := make([]any, 0, len(s))
+	for _, v := range s {
+		l = append(l, v)
+	}
+	return l
+}
+
+// directoryAttestation initializes the test results with the digest of the
+// files in the working directory.
+func directoryAttestation(opts *Options) (*v0.TestResult, error) {
+	digest, err := dirhash.Digest(opts.WorkDir, opts.SourceIgnore)
+	if err != nil {
+		return nil, fmt.Errorf("hashing working directory: %w", err)
+	}
+
+	abs, err := filepath.Abs(opts.WorkDir)
+	if err != nil {
+		return nil, fmt.Errorf("resolving working directory: %w", err)
+	}
+
+	rd, err := models.NewSourceDescriptor(
+		models.SourceDirectory, filepath.Base(abs), digest,
+		map[string]any{"ignore": stringList(slices.Concat(dirhash.DefaultIgnore, opts.SourceIgnore))},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &v0.TestResult{Configuration: []*v1.ResourceDescriptor{rd}}, nil
+}
+
+// archiveAttestation initializes the test results with the digest of the
+// source archive.
+func archiveAttestation(opts *Options) (*v0.TestResult, error) {
+	if opts.SourceArchive == "" {
+		return nil, errors.New("no source archive set")
+	}
+	file, err := models.FileDescriptor(opts.SourceArchive, filepath.Base(opts.SourceArchive))
+	if err != nil {
+		return nil, fmt.Errorf("hashing source archive: %w", err)
+	}
+	rd, err := models.NewSourceDescriptor(models.SourceArchive, file.GetName(), file.GetDigest()["sha256"], nil)
+	if err != nil {
+		return nil, err
+	}
+	return &v0.TestResult{Configuration: []*v1.ResourceDescriptor{rd}}, nil
 }
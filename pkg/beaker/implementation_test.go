@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+package beaker
+
+import (
+	"testing"
+
+	v1 "github.com/in-toto/attestation/go/v1"
+	"github.com/stretchr/testify/require"
+
+	"github.com/carabiner-dev/beaker/internal/git"
+)
+
+func TestMarkDirty(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name     string
+		version  string
+		expected string
+	}{
+		{"tagged", "v1.0.1-1+2bce182a", "v1.0.1-1+2bce182a-dirty"},
+		{"untagged", "", "01234567-dirty"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			rd := &v1.ResourceDescriptor{
+				Name:   tc.version,
+				Digest: map[string]string{"gitCommit": testCommit},
+			}
+			require.NoError(t, markDirty(rd, &git.Status{
+				Modified: []string{"main.go"}, Staged: []string{}, Untracked: []string{"new.go"}, Digest: "abc",
+			}))
+			require.Equal(t, tc.expected, rd.GetName())
+			annotations := rd.GetAnnotations().AsMap()
+			require.Equal(t, true, annotations["dirty"])
+			require.Equal(t, []any{"main.go"}, annotations["modified"])
+			require.Equal(t, []any{"new.go"}, annotations["untracked"])
+			require.Equal(t, "abc", annotations["changesSha256"])
+		})
+	}
+}
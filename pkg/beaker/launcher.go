@@ -4,33 +4,74 @@
 package beaker
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"time"
 
 	ajson "github.com/carabiner-dev/collector/predicate/json"
 	"github.com/carabiner-dev/collector/statement/intoto"
+	v0 "github.com/in-toto/attestation/go/predicates/test_result/v0"
+	v1 "github.com/in-toto/attestation/go/v1"
+	sdsse "github.com/sigstore/protobuf-specs/gen/pb-go/dsse"
+	"github.com/sirupsen/logrus"
 	"google.golang.org/protobuf/encoding/protojson"
-	"sigs.k8s.io/release-utils/helpers"
+	"google.golang.org/protobuf/proto"
 
-	"github.com/carabiner-dev/beaker/pkg/runners/golang"
-	"github.com/carabiner-dev/beaker/pkg/runners/npm"
+	"github.com/carabiner-dev/beaker/models"
+	"github.com/carabiner-dev/beaker/pkg/signing"
 )
 
+const (
+	resultPass    = "pass"
+	resultFail    = "fail"
+	resultError   = "error"
+	resultTimeout = "timeout"
+)
+
+// resultSeverity ranks the results when merging several runs, the most
+// severe result wins.
+var resultSeverity = map[string]int{
+	resultPass:    0,
+	resultFail:    1,
+	resultError:   2,
+	resultTimeout: 3,
+}
+
+// ErrTimeout is returned when the tests don't finish within the configured
+// timeout. The attestation is still written, with its result set to timeout.
+var ErrTimeout = errors.New("tests timed out")
+
+// ErrDirtyTree is returned when a clean working tree is required and the
+// repository has uncommitted changes.
+var ErrDirtyTree = errors.New("working tree has uncommitted changes")
+
 func New(funcs ...OptFn) (*Launcher, error) {
 	opts := Options{
-		Writer:  os.Stdout,
-		WorkDir: ".",
-		Attest:  true,
+		Writer:        os.Stdout,
+		WorkDir:       ".",
+		Attest:        true,
+		ExitPolicy:    ExitPolicyError,
+		Format:        FormatJSON,
+		Source:        SourceGit,
+		VersionFormat: VersionFormatSemver,
 	}
 	for _, f := range funcs {
 		if err := f(&opts); err != nil {
 			return nil, err
 		}
 	}
+	if opts.Signer != nil && !opts.Attest {
+		return nil, errors.New("signing requires the output to be an attestation")
+	}
+	if opts.Format == FormatBundle && opts.Signer == nil {
+		return nil, errors.New("bundles require a signing key")
+	}
 	return &Launcher{
 		impl:    &defaultLauncherImplementation{},
 		Options: opts,
@@ -42,23 +83,130 @@ type Launcher struct {
 	Options Options
 }
 
-// Test launches the test suite defined in the launch pack
-func (l *Launcher) Test(ctx context.Context, pack *LaunchPack) error {
+// Test launches the test suites defined in the launch packs and writes
+// the results. When more than one pack is passed, their results are
+// merged into a single predicate.
+func (l *Launcher) Test(ctx context.Context, packs ...*LaunchPack) error {
+	att, runErr := l.Run(ctx, packs...)
+	if runErr != nil && !errors.Is(runErr, ErrTimeout) {
+		return runErr
+	}
+
+	if err := l.Write(ctx, att); err != nil {
+		return err
+	}
+	return runErr
+}
+
+// Run launches the test suites defined in the launch packs and returns
+// their results without writing them. The start and finish times of the
+// run are recorded in the test details. When the tests time out, the
+// partial results are returned along ErrTimeout.
+func (l *Launcher) Run(ctx context.Context, packs ...*LaunchPack) (*v0.TestResult, error) {
+	if len(packs) == 0 {
+		return nil, errors.New("no launch packs to run")
+	}
+
 	att, err := l.impl.InitAttestation(ctx, &l.Options)
 	if err != nil {
-		return fmt.Errorf("initializing attestation: %w", err)
+		return nil, fmt.Errorf("initializing attestation: %w", err)
 	}
+	att = l.addConfiguration(ctx, att, packs)
 
-	output, _, err := pack.Runner.Run(ctx)
-	if err != nil {
-		return fmt.Errorf("runner error: %w", err)
+	runCtx := ctx
+	if l.Options.Timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, l.Options.Timeout)
+		defer cancel()
 	}
 
-	att, err = pack.Parser.ParseResults(ctx, att, output)
+	started := time.Now().UTC()
+	att, output, err := l.runPacks(runCtx, att, packs)
+	if att != nil {
+		if terr := recordRunTimes(att, started, time.Now().UTC()); terr != nil {
+			return nil, terr
+		}
+	}
+
+	// The log is written even when the run failed, to help debug it
+	if l.Options.LogPath != "" {
+		if lerr := l.writeLog(att, output); lerr != nil {
+			return nil, lerr
+		}
+	}
+	return att, err
+}
+
+// writeLog writes the raw output of the runners to the log file and
+// references it in the configuration of the results.
+func (l *Launcher) writeLog(att *v0.TestResult, output []byte) error {
+	if err := os.WriteFile(l.Options.LogPath, output, 0o644); err != nil { //nolint:gosec // The log is not secret
+		return fmt.Errorf("writing runner log: %w", err)
+	}
+	if att != nil {
+		att.Configuration = append(att.Configuration, models.NewLogDescriptor(l.Options.LogPath, output))
+	}
+	return nil
+}
+
+// addConfiguration records in the predicate configuration the digest of
+// the configuration file and the descriptors contributed by the runners.
+// Descriptors that cannot be computed are skipped with a warning, they
+// don't stop the tests from running.
+func (l *Launcher) addConfiguration(ctx context.Context, att *v0.TestResult, packs []*LaunchPack) *v0.TestResult {
+	rds := []*v1.ResourceDescriptor{}
+	if l.Options.ConfigFile != "" {
+		rd, err := models.FileDescriptor(l.Options.ConfigFile, filepath.Base(l.Options.ConfigFile))
+		if err != nil {
+			logrus.Warnf("unable to record the configuration file: %v", err)
+		} else {
+			rds = append(rds, rd)
+		}
+	}
+
+	for _, pack := range packs {
+		provider, ok := pack.Runner.(models.ConfigurationProvider)
+		if !ok {
+			continue
+		}
+		prds, err := provider.Configuration(ctx)
+		if err != nil {
+			logrus.Warnf("unable to record the runner configuration: %v", err)
+			continue
+		}
+		// Runners of the same kind contribute the same descriptors
+		for _, rd := range prds {
+			if !slices.ContainsFunc(rds, func(d *v1.ResourceDescriptor) bool { return proto.Equal(d, rd) }) {
+				rds = append(rds, rd)
+			}
+		}
+	}
+
+	if len(rds) == 0 {
+		return att
+	}
+	if att == nil {
+		att = &v0.TestResult{}
+	}
+	att.Configuration = append(att.Configuration, rds...)
+	return att
+}
+
+// recordRunTimes sets the start and finish times of the run in the test
+// details.
+func recordRunTimes(att *v0.TestResult, started, finished time.Time) error {
+	details, err := models.GetTestDetails(att)
 	if err != nil {
-		return fmt.Errorf("parsing results: %w", err)
+		return err
 	}
+	details.StartedOn = started.Round(0)
+	details.FinishedOn = finished.Round(0)
+	return models.SetTestDetails(att, details)
+}
 
+// Write outputs the test results to the configured writer as a predicate
+// or, when attesting, as a statement, signed envelope or bundle.
+func (l *Launcher) Write(ctx context.Context, att *v0.TestResult) error {
 	if l.Options.Writer == nil {
 		return fmt.Errorf("tests ran successfully but no writer was configured")
 	}
@@ -74,60 +222,275 @@ func (l *Launcher) Test(ctx context.Context, pack *LaunchPack) error {
 	}
 
 	// If attesting output the statement, not a predicate
-	if l.Options.Attest {
-		// Ensure a subject is present
-		if len(att.GetConfiguration()) == 0 {
-			return fmt.Errorf("unable to attest no repository data found in configuration")
-		}
-		pred, err := ajson.New(
-			ajson.WithJson(jdata),
-			ajson.WithType("https://in-toto.io/attestation/test-result/v0.1"),
-		)
-		if err != nil {
-			return fmt.Errorf("creating new predicate: %w", err)
+	if !l.Options.Attest {
+		if _, err := l.Options.Writer.Write(jdata); err != nil {
+			return fmt.Errorf("wiriting attestation data: %w", err)
 		}
+		return nil
+	}
 
-		s := intoto.NewStatement(
-			intoto.WithPredicate(pred),
-			intoto.WithSubject(att.GetConfiguration()[0]),
-		)
+	// Use the custom subjects or ensure the source one is present
+	subjects := l.Options.Subjects
+	if len(subjects) == 0 {
+		subject := subjectDescriptor(att)
+		if subject == nil {
+			return fmt.Errorf("unable to attest no source data found in configuration (run in a git repository or use a directory or archive source)")
+		}
+		subjects = []*v1.ResourceDescriptor{subject}
+	}
+	pred, err := ajson.New(
+		ajson.WithJson(jdata),
+		ajson.WithType(PredicateType),
+	)
+	if err != nil {
+		return fmt.Errorf("creating new predicate: %w", err)
+	}
 
+	s := intoto.NewStatement(
+		intoto.WithPredicate(pred),
+		intoto.WithSubject(subjects...),
+	)
+
+	if l.Options.Signer == nil {
 		enc := json.NewEncoder(l.Options.Writer)
 		enc.SetIndent("", "  ")
 		if err := enc.Encode(s); err != nil {
 			return fmt.Errorf("marshaling statement: %w", err)
 		}
-	} else {
-		if _, err := l.Options.Writer.Write(jdata); err != nil {
-			return fmt.Errorf("wiriting attestation data: %w", err)
+		return nil
+	}
+
+	env, err := signStatement(l.Options.Signer, s)
+	if err != nil {
+		return err
+	}
+
+	var output proto.Message = env
+	if l.Options.Format == FormatBundle {
+		output, err = signing.NewBundle(env, l.Options.Signer, l.Options.Certificate)
+		if err != nil {
+			return fmt.Errorf("building bundle: %w", err)
 		}
 	}
 
+	data, err := protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(output)
+	if err != nil {
+		return fmt.Errorf("marshaling signed attestation: %w", err)
+	}
+	if _, err := l.Options.Writer.Write(data); err != nil {
+		return fmt.Errorf("writing signed attestation: %w", err)
+	}
 	return nil
 }
 
-// LaunchPackFromRepo reads a codebase and returns a launchpack
-func LaunchPackFromRepo(path string) (*LaunchPack, error) {
-	switch {
-	case helpers.Exists(filepath.Join(path, "go.mod")):
-		gorunner, err := golang.New(golang.WithWorkDir(path))
+// signStatement wraps the statement in a DSSE envelope signed by signer
+func signStatement(signer *signing.Signer, statement any) (*sdsse.Envelope, error) {
+	data, err := json.Marshal(statement)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling statement: %w", err)
+	}
+	env, err := signer.SignPayload(signing.PayloadType, data)
+	if err != nil {
+		return nil, fmt.Errorf("signing statement: %w", err)
+	}
+	return env, nil
+}
+
+// runPacks executes the launch packs. When there is more than one, each
+// pack is parsed into its own result and then merged into att. The raw
+// output of all the runners is returned along the results. If the tests
+// time out, the results collected so far are returned with ErrTimeout.
+func (l *Launcher) runPacks(ctx context.Context, att *v0.TestResult, packs []*LaunchPack) (*v0.TestResult, []byte, error) {
+	if len(packs) == 1 {
+		return l.runPack(ctx, packs[0], att)
+	}
+
+	if att == nil {
+		att = &v0.TestResult{}
+	}
+	att.Result = resultPass
+	var log bytes.Buffer
+	for i, pack := range packs {
+		res, output, err := l.runPack(ctx, pack, &v0.TestResult{Configuration: slices.Clone(att.GetConfiguration())})
+		log.Write(output)
+		if res != nil {
+			if merr := mergeResults(att, res); merr != nil {
+				return nil, log.Bytes(), fmt.Errorf("launch pack #%d: %w", i+1, merr)
+			}
+		}
+		if errors.Is(err, ErrTimeout) {
+			return att, log.Bytes(), err
+		}
+		if err != nil {
+			return nil, log.Bytes(), fmt.Errorf("launch pack #%d: %w", i+1, err)
+		}
+	}
+	return att, log.Bytes(), nil
+}
+
+// runPack executes a launch pack and parses its output into att, the raw
+// output of the runner is returned along the results. If the runner times
+// out, the partial output is parsed and the result is set to timeout.
+// Otherwise, the runner exit status is reconciled with the parsed results
+// according to the exit policy.
+func (l *Launcher) runPack(ctx context.Context, pack *LaunchPack, att *v0.TestResult) (*v0.TestResult, []byte, error) {
+	if err := pack.Verify(); err != nil {
+		return nil, nil, err
+	}
+
+	output, pass, err := l.impl.RunLaunchPack(ctx, &l.Options, pack)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return timedOutResult(context.WithoutCancel(ctx), pack, att, output), output, fmt.Errorf("%w: %w", ErrTimeout, err)
+	}
+	if err != nil {
+		return nil, output, err
+	}
+
+	att, err = pack.Parser.ParseResults(ctx, att, output)
+	if err != nil {
+		return nil, output, fmt.Errorf("parsing results: %w", err)
+	}
+
+	reconcileResult(att, pass, l.Options.ExitPolicy)
+	return att, output, nil
+}
+
+// reconcileResult adjusts the parsed result with the exit status of the
+// runner. A runner that failed without reporting failed tests (a build
+// error, a crash before the tests ran) results in an error.
+func reconcileResult(att *v0.TestResult, pass bool, policy ExitPolicy) {
+	if policy == ExitPolicyIgnore || att == nil {
+		return
+	}
+
+	if !pass && att.GetResult() == resultPass {
+		logrus.Warn("test runner failed but no test failures were found in its output")
+		att.Result = resultError
+		return
+	}
+
+	if policy == ExitPolicyStrict && len(att.GetPassedTests())+len(att.GetFailedTests()) == 0 {
+		logrus.Warn("no test results were found in the runner output")
+		att.Result = resultError
+	}
+}
+
+// timedOutResult parses the output of a runner that timed out. Errors are
+// ignored as the output is likely truncated, the tests that completed are
+// recorded when possible.
+func timedOutResult(ctx context.Context, pack *LaunchPack, att *v0.TestResult, output []byte) *v0.TestResult {
+	if res, err := pack.Parser.ParseResults(ctx, att, output); err == nil && res != nil {
+		att = res
+	}
+	if att == nil {
+		att = &v0.TestResult{}
+	}
+	att.Result = resultTimeout
+	return att
+}
+
+// mergeResults adds the test outcomes and details in src to dst. The
+// result of dst is only changed when the result of src is more severe.
+func mergeResults(dst, src *v0.TestResult) error {
+	dst.PassedTests = append(dst.PassedTests, src.GetPassedTests()...)
+	dst.WarnedTests = append(dst.WarnedTests, src.GetWarnedTests()...)
+	dst.FailedTests = append(dst.FailedTests, src.GetFailedTests()...)
+	if severity(src.GetResult()) > severity(dst.GetResult()) {
+		dst.Result = src.GetResult()
+	}
+
+	details, err := models.GetTestDetails(dst)
+	if err != nil {
+		return err
+	}
+	srcDetails, err := models.GetTestDetails(src)
+	if err != nil {
+		return err
+	}
+	details.Merge(srcDetails)
+	return models.SetTestDetails(dst, details)
+}
+
+// subjectDescriptor returns the descriptor to use as the attestation
+// subject: the source descriptor, the first one in the configuration with
+// a git commit digest or marked as a directory or archive source.
+func subjectDescriptor(att *v0.TestResult) *v1.ResourceDescriptor {
+	for _, rd := range att.GetConfiguration() {
+		if _, ok := rd.GetDigest()["gitCommit"]; ok || models.IsSourceDescriptor(rd) {
+			return rd
+		}
+	}
+	return nil
+}
+
+// severity returns the rank of a result, unknown results are ranked as
+// failures.
+func severity(result string) int {
+	if s, ok := resultSeverity[result]; ok {
+		return s
+	}
+	return resultSeverity[resultFail]
+}
+
+// LaunchPacksFromConfig builds the launch packs defined in a configuration.
+// If the configuration defines no runners, the launch pack is detected from
+// the codebase at path.
+func LaunchPacksFromConfig(path string, conf *Config) ([]*LaunchPack, error) {
+	if conf == nil || len(conf.Runners) == 0 {
+		pack, err := LaunchPackFromRepo(path)
 		if err != nil {
-			return nil, fmt.Errorf("initializing go launchpack: %w", err)
-		}
-		return &LaunchPack{
-			Runner: gorunner,
-			Parser: gorunner,
-		}, nil
-	case helpers.Exists(filepath.Join(path, "package.json")):
-		npmrunner, err := npm.New(npm.WithWorkDir(path))
+			return nil, err
+		}
+		return []*LaunchPack{pack}, nil
+	}
+
+	packs := []*LaunchPack{}
+	for i := range conf.Runners {
+		pack, err := launchPackFromRunnerConfig(path, &conf.Runners[i])
 		if err != nil {
-			return nil, fmt.Errorf("initializing npm launchpack: %w", err)
+			return nil, fmt.Errorf("runner #%d (%s): %w", i+1, conf.Runners[i].Name, err)
 		}
-		return &LaunchPack{
-			Runner: npmrunner,
-			Parser: npmrunner,
-		}, nil
-	default:
+		packs = append(packs, pack)
+	}
+	return packs, nil
+}
+
+// launchPackFromRunnerConfig creates a launch pack from a runner entry
+func launchPackFromRunnerConfig(path string, rc *RunnerConfig) (*LaunchPack, error) {
+	if err := rc.Validate(); err != nil {
+		return nil, err
+	}
+
+	def, ok := getRunner(rc.Name)
+	if !ok {
+		return nil, fmt.Errorf("unknown runner %q", rc.Name)
+	}
+
+	pack, err := def.New(path, rc)
+	if err != nil {
+		return nil, err
+	}
+
+	if rc.Parser != "" {
+		factory, ok := getParser(rc.Parser)
+		if !ok {
+			return nil, fmt.Errorf("unknown parser %q", rc.Parser)
+		}
+		parser, err := factory(path, rc)
+		if err != nil {
+			return nil, fmt.Errorf("initializing parser: %w", err)
+		}
+		pack.Parser = parser
+	}
+	return pack, nil
+}
+
+// LaunchPackFromRepo reads a codebase and returns a launchpack for the
+// first registered runner that detects it.
+func LaunchPackFromRepo(path string) (*LaunchPack, error) {
+	def, ok := detectRunner(path)
+	if !ok {
 		return nil, errors.New("unable to detect the language ecosystem")
 	}
+	return launchPackFromRunnerConfig(path, &RunnerConfig{Name: def.Name})
 }
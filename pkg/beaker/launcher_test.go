@@ -0,0 +1,41 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+package beaker
+
+import (
+	"testing"
+
+	v0 "github.com/in-toto/attestation/go/predicates/test_result/v0"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconcileResult(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name     string
+		policy   ExitPolicy
+		pass     bool
+		result   string
+		passed   []string
+		failed   []string
+		expected string
+	}{
+		{"ignore-build-failure", ExitPolicyIgnore, false, resultPass, nil, nil, resultPass},
+		{"error-build-failure", ExitPolicyError, false, resultPass, nil, nil, resultError},
+		{"error-build-failure-with-passes", ExitPolicyError, false, resultPass, []string{"TestA"}, nil, resultError},
+		{"error-test-failures", ExitPolicyError, false, resultFail, []string{"TestA"}, []string{"TestB"}, resultFail},
+		{"error-pass", ExitPolicyError, true, resultPass, []string{"TestA"}, nil, resultPass},
+		{"error-no-tests", ExitPolicyError, true, resultPass, nil, nil, resultPass},
+		{"strict-no-tests", ExitPolicyStrict, true, resultPass, nil, nil, resultError},
+		{"strict-pass", ExitPolicyStrict, true, resultPass, []string{"TestA"}, nil, resultPass},
+		{"strict-build-failure", ExitPolicyStrict, false, resultPass, nil, nil, resultError},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			att := &v0.TestResult{Result: tc.result, PassedTests: tc.passed, FailedTests: tc.failed}
+			reconcileResult(att, tc.pass, tc.policy)
+			require.Equal(t, tc.expected, att.GetResult())
+		})
+	}
+}
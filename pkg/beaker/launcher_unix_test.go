@@ -0,0 +1,297 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+//go:build unix
+
+package beaker
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	v0 "github.com/in-toto/attestation/go/predicates/test_result/v0"
+	v1 "github.com/in-toto/attestation/go/v1"
+	sdsse "github.com/sigstore/protobuf-specs/gen/pb-go/dsse"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/carabiner-dev/beaker/models"
+	"github.com/carabiner-dev/beaker/pkg/runners/golang"
+	"github.com/carabiner-dev/beaker/pkg/runners/shell"
+	"github.com/carabiner-dev/beaker/pkg/signing"
+)
+
+// fakeRepoImplementation runs the packs but returns a fixed repository
+// descriptor instead of reading it from git.
+type fakeRepoImplementation struct {
+	defaultLauncherImplementation
+}
+
+func (*fakeRepoImplementation) InitAttestation(context.Context, *Options) (*v0.TestResult, error) {
+	return &v0.TestResult{
+		Configuration: []*v1.ResourceDescriptor{{
+			Uri:    "git+https://example.com/repo",
+			Digest: map[string]string{"gitCommit": "0123456789abcdef0123456789abcdef01234567"},
+		}},
+	}, nil
+}
+
+// configRunner is a runner contributing configuration descriptors
+type configRunner struct {
+	*shell.Runner
+	rds []*v1.ResourceDescriptor
+}
+
+func (r *configRunner) Configuration(context.Context) ([]*v1.ResourceDescriptor, error) {
+	return r.rds, nil
+}
+
+// passScript is a runner script printing a passing go test -json event
+const passScript = `echo '{"Action":"pass","Package":"example.com/m","Test":"TestA"}'`
+
+// testRunner returns a shell runner executing script
+func testRunner(t *testing.T, script string) *shell.Runner {
+	t.Helper()
+	runner, err := shell.New(shell.WithCommand("sh"), shell.WithArguments([]string{"-c", script}))
+	require.NoError(t, err)
+	return runner
+}
+
+// testPack returns a pack running script and parsing its output as go test
+// -json events.
+func testPack(t *testing.T, script string) *LaunchPack {
+	t.Helper()
+	parser, err := golang.New()
+	require.NoError(t, err)
+	return &LaunchPack{Runner: testRunner(t, script), Parser: parser}
+}
+
+// testLauncher returns a launcher working in a temporary directory. The
+// output is discarded unless the options set a writer.
+func testLauncher(t *testing.T, funcs ...OptFn) *Launcher {
+	t.Helper()
+	launcher, err := New(append([]OptFn{WithWorkDir(t.TempDir()), WithWriter(io.Discard)}, funcs...)...)
+	require.NoError(t, err)
+	return launcher
+}
+
+func TestLauncherConfiguration(t *testing.T) {
+	t.Parallel()
+	pack := testPack(t, passScript)
+	pack.Runner = &configRunner{
+		Runner: testRunner(t, passScript),
+		rds:    []*v1.ResourceDescriptor{{Name: "tool", Digest: map[string]string{"sha256": "abc"}}},
+	}
+
+	confPath := filepath.Join(t.TempDir(), DefaultConfigFile)
+	require.NoError(t, os.WriteFile(confPath, []byte("attest: true\n"), 0o600))
+
+	launcher := testLauncher(t, WithConfigFile(confPath))
+	launcher.impl = &fakeRepoImplementation{}
+
+	// The descriptors of runners of the same kind are recorded once
+	att, err := launcher.Run(t.Context(), pack, &LaunchPack{Runner: pack.Runner, Parser: pack.Parser})
+	require.NoError(t, err)
+
+	names := []string{}
+	for _, rd := range att.GetConfiguration() {
+		names = append(names, rd.GetName())
+	}
+	require.Equal(t, []string{"", DefaultConfigFile, "tool", models.DetailsDescriptorName}, names)
+	require.Equal(t, "git+https://example.com/repo", subjectDescriptor(att).GetUri())
+}
+
+func TestLauncherTimeout(t *testing.T) {
+	t.Parallel()
+
+	// The fake runner emits a passing test and then hangs
+	pack := testPack(t, `echo '{"Action":"pass","Package":"example.com/m","Test":"TestFast"}'; sleep 30`)
+
+	var b bytes.Buffer
+	launcher := testLauncher(t, WithWriter(&b), WithAttest(false), WithTimeout(300*time.Millisecond))
+
+	start := time.Now()
+	err := launcher.Test(t.Context(), pack)
+	require.ErrorIs(t, err, ErrTimeout)
+	require.Less(t, time.Since(start), 10*time.Second)
+
+	res := map[string]any{}
+	require.NoError(t, json.Unmarshal(b.Bytes(), &res))
+	require.Equal(t, resultTimeout, res["result"])
+	require.Equal(t, []any{"TestFast"}, res["passedTests"])
+}
+
+func TestLauncherBuildFailure(t *testing.T) {
+	t.Parallel()
+
+	// go test -json emits no failed tests when the package does not build
+	pack := testPack(t, `printf '%s\n' '{"Action":"output","Package":"example.com/m","Output":"FAIL\texample.com/m [build failed]\n"}'; exit 1`)
+
+	var b bytes.Buffer
+	launcher := testLauncher(t, WithWriter(&b), WithAttest(false))
+	require.NoError(t, launcher.Test(t.Context(), pack))
+
+	res := map[string]any{}
+	require.NoError(t, json.Unmarshal(b.Bytes(), &res))
+	require.Equal(t, resultError, res["result"])
+}
+
+func TestLauncherRunTimes(t *testing.T) {
+	t.Parallel()
+	pack := testPack(t, `echo '{"Action":"pass","Package":"example.com/m","Test":"TestA","Elapsed":0.2}'`)
+	launcher := testLauncher(t, WithAttest(false))
+
+	start := time.Now()
+	att, err := launcher.Run(t.Context(), pack)
+	require.NoError(t, err)
+
+	details, err := models.GetTestDetails(att)
+	require.NoError(t, err)
+	require.Equal(t, map[string]float64{"TestA": 0.2}, details.Durations)
+	require.False(t, details.StartedOn.Before(start.Truncate(time.Second)))
+	require.False(t, details.FinishedOn.Before(details.StartedOn))
+	require.False(t, details.FinishedOn.After(time.Now()))
+}
+
+func TestLauncherLog(t *testing.T) {
+	t.Parallel()
+	logPath := filepath.Join(t.TempDir(), "tests.log")
+	launcher := testLauncher(t, WithAttest(false), WithLogPath(logPath))
+	att, err := launcher.Run(t.Context(), testPack(t, passScript))
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"Test":"TestA"`)
+
+	sum := sha256.Sum256(data)
+	var log *v1.ResourceDescriptor
+	for _, rd := range att.GetConfiguration() {
+		if models.IsLogDescriptor(rd) {
+			log = rd
+		}
+	}
+	require.NotNil(t, log)
+	require.Equal(t, "tests.log", log.GetName())
+	require.Equal(t, hex.EncodeToString(sum[:]), log.GetDigest()["sha256"])
+	require.Nil(t, subjectDescriptor(att))
+}
+
+func TestLauncherSign(t *testing.T) {
+	t.Parallel()
+	signer := testSigner(t)
+
+	_, err := New(WithAttest(false), WithSigner(signer))
+	require.Error(t, err, "signing requires attest")
+
+	var b bytes.Buffer
+	launcher := testLauncher(t, WithWriter(&b), WithSigner(signer))
+	launcher.impl = &fakeRepoImplementation{}
+	require.NoError(t, launcher.Test(t.Context(), testPack(t, passScript)))
+
+	env := &sdsse.Envelope{}
+	require.NoError(t, protojson.Unmarshal(b.Bytes(), env))
+	require.Equal(t, signing.PayloadType, env.GetPayloadType())
+	require.NoError(t, testVerifier(t, signer).Verify(env))
+	require.True(t, json.Valid(env.GetPayload()))
+}
+
+func TestLauncherBundle(t *testing.T) {
+	t.Parallel()
+	_, err := New(WithFormat(FormatBundle))
+	require.Error(t, err, "bundles require a signer")
+
+	signer := testSigner(t)
+	var b bytes.Buffer
+	launcher := testLauncher(t, WithWriter(&b), WithSigner(signer), WithFormat(FormatBundle))
+	launcher.impl = &fakeRepoImplementation{}
+	require.NoError(t, launcher.Test(t.Context(), testPack(t, passScript)))
+
+	env, err := signing.ParseBundle(b.Bytes())
+	require.NoError(t, err)
+	require.NoError(t, testVerifier(t, signer).Verify(env))
+}
+
+func TestLauncherSource(t *testing.T) {
+	t.Parallel()
+	t.Run("directory", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0o600))
+		output := filepath.Join(dir, "tests.intoto.json")
+		f, err := os.Create(output)
+		require.NoError(t, err)
+		defer f.Close() //nolint:errcheck
+
+		launcher := testLauncher(t,
+			WithWorkDir(dir), WithWriter(f),
+			WithSource(SourceDirectory), WithSourceIgnore("/tests.intoto.json"),
+		)
+		require.NoError(t, launcher.Test(t.Context(), testPack(t, passScript)))
+
+		data, err := os.ReadFile(output)
+		require.NoError(t, err)
+		statement := struct {
+			Subject []*v1.ResourceDescriptor `json:"subject"`
+		}{}
+		require.NoError(t, json.Unmarshal(data, &statement))
+		require.Len(t, statement.Subject, 1)
+		require.Equal(t, filepath.Base(dir), statement.Subject[0].GetName())
+
+		// The subject is the digest of the codebase without the attestation
+		require.NoError(t, os.Remove(output))
+		manifest := "f " + sha256Hex("package main\n") + " main.go\n"
+		require.Equal(t, sha256Hex(manifest), statement.Subject[0].GetDigest()["sha256"])
+	})
+
+	t.Run("archive", func(t *testing.T) {
+		t.Parallel()
+		archive := filepath.Join(t.TempDir(), "src.tar.gz")
+		require.NoError(t, os.WriteFile(archive, []byte("archive data"), 0o600))
+
+		launcher := testLauncher(t, WithSourceArchive(archive))
+		att, err := launcher.Run(t.Context(), testPack(t, passScript))
+		require.NoError(t, err)
+
+		subject := subjectDescriptor(att)
+		require.NotNil(t, subject)
+		require.Equal(t, "src.tar.gz", subject.GetName())
+		require.Equal(t, sha256Hex("archive data"), subject.GetDigest()["sha256"])
+		require.True(t, models.IsSourceDescriptor(subject))
+	})
+
+	t.Run("custom-subjects", func(t *testing.T) {
+		t.Parallel()
+		b := &bytes.Buffer{}
+		digest := sha256Hex("artifact")
+		launcher := testLauncher(t, WithWriter(b), WithSubject("app=sha256:"+digest), WithSubject("lib=sha1:"+testCommit))
+		require.NoError(t, launcher.Test(t.Context(), testPack(t, passScript)))
+
+		statement := struct {
+			Subject []*v1.ResourceDescriptor `json:"subject"`
+		}{}
+		require.NoError(t, json.Unmarshal(b.Bytes(), &statement))
+		require.Len(t, statement.Subject, 2)
+		require.Equal(t, digest, statement.Subject[0].GetDigest()["sha256"])
+		require.Equal(t, testCommit, statement.Subject[1].GetDigest()["sha1"])
+	})
+
+	t.Run("no-source", func(t *testing.T) {
+		t.Parallel()
+		launcher := testLauncher(t)
+		require.Error(t, launcher.Test(t.Context(), testPack(t, passScript)))
+	})
+}
+
+func sha256Hex(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
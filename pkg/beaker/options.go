@@ -4,19 +4,161 @@
 package beaker
 
 import (
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"io"
+	"path"
+	"slices"
+	"time"
 
+	v1 "github.com/in-toto/attestation/go/v1"
 	"sigs.k8s.io/release-utils/helpers"
+
+	"github.com/carabiner-dev/beaker/internal/git"
+	"github.com/carabiner-dev/beaker/pkg/signing"
 )
 
 type OptFn func(*Options) error
 
+// ExitPolicy defines how the exit status of a test runner is reconciled
+// with the results parsed from its output.
+type ExitPolicy string
+
+const (
+	// ExitPolicyIgnore trusts the parsed results, the exit status of the
+	// runner is not checked.
+	ExitPolicyIgnore ExitPolicy = "ignore"
+
+	// ExitPolicyError sets the result to error when the runner fails but
+	// no failed tests were parsed, for example when the code does not build.
+	ExitPolicyError ExitPolicy = "error"
+
+	// ExitPolicyStrict applies ExitPolicyError and also sets the result
+	// to error when the runner succeeds but no tests were parsed.
+	ExitPolicyStrict ExitPolicy = "strict"
+)
+
+// ExitPolicies lists the valid exit policies
+var ExitPolicies = []ExitPolicy{ExitPolicyIgnore, ExitPolicyError, ExitPolicyStrict}
+
+// OutputFormat selects how the attestation is written
+type OutputFormat string
+
+const (
+	// FormatJSON writes the predicate, the statement or, when signing,
+	// the DSSE envelope as JSON.
+	FormatJSON OutputFormat = "json"
+
+	// FormatBundle writes a sigstore bundle with the signed DSSE envelope
+	// and the verification material of the signer.
+	FormatBundle OutputFormat = "bundle"
+)
+
+// OutputFormats lists the valid output formats
+var OutputFormats = []OutputFormat{FormatJSON, FormatBundle}
+
+// SourceMode selects how the tested sources are identified in the
+// attestation subject.
+type SourceMode string
+
+const (
+	// SourceGit uses the commit checked out in the git repository
+	SourceGit SourceMode = "git"
+
+	// SourceDirectory uses a digest of the files in the working directory,
+	// for sources not tracked in git such as exported source trees.
+	SourceDirectory SourceMode = "dir"
+
+	// SourceArchive uses the digest of an archive of the sources, such as
+	// the tarball the working directory was extracted from.
+	SourceArchive SourceMode = "archive"
+)
+
+// SourceModes lists the valid source modes
+var SourceModes = []SourceMode{SourceGit, SourceDirectory, SourceArchive}
+
+// VersionFormat is the format of the version recorded for commits past
+// the latest tag.
+type VersionFormat = git.VersionFormat
+
+const (
+	// VersionFormatSemver appends the commits since the tag and the commit
+	// hash as semver build metadata: v1.0.1-3+2bce182a
+	VersionFormatSemver = git.VersionFormatSemver
+
+	// VersionFormatDescribe mimics git describe --tags: v1.0.1-3-g2bce182
+	VersionFormatDescribe = git.VersionFormatDescribe
+)
+
+// VersionFormats lists the valid version formats
+var VersionFormats = git.VersionFormats
+
 type Options struct {
 	Writer  io.Writer
 	WorkDir string
 	Attest  bool
+
+	// Timeout limits the time the test runners can take. Zero means
+	// no limit.
+	Timeout time.Duration
+
+	// ExitPolicy controls how the runner exit status affects the result
+	ExitPolicy ExitPolicy
+
+	// Signer wraps the statement in a signed DSSE envelope. Signing
+	// requires Attest to be set.
+	Signer *signing.Signer
+
+	// Certificate of the signing key, included in bundles as the
+	// verification material.
+	Certificate *x509.Certificate
+
+	// Format of the output. Bundles require a signer.
+	Format OutputFormat
+
+	// LogPath is the file where the raw output of the runners is written.
+	// The log is referenced from the attestation by its digest. Empty
+	// disables the log.
+	LogPath string
+
+	// ConfigFile is the path of the beaker configuration file used, its
+	// digest is recorded in the predicate configuration.
+	ConfigFile string
+
+	// Source selects how the tested sources are identified
+	Source SourceMode
+
+	// SourceIgnore lists patterns of files that are not part of the
+	// sources. They are excluded from the digest of the working directory
+	// in SourceDirectory mode and from the dirty tree check in git mode.
+	SourceIgnore []string
+
+	// SourceArchive is the path to the archive hashed in SourceArchive mode
+	SourceArchive string
+
+	// Remote is the name of the git remote recorded in the repository
+	// descriptor. When empty, upstream or origin are preferred.
+	Remote string
+
+	// RepoURI overrides the repository URL recorded in the descriptor
+	RepoURI string
+
+	// RequireClean refuses to run the tests when the git working tree has
+	// uncommitted changes.
+	RequireClean bool
+
+	// TagPattern filters the git tags used to compute the version of the
+	// sources, such as "v*". Empty considers all tags.
+	TagPattern string
+
+	// VersionFormat is the format of the version of commits past the
+	// latest tag.
+	VersionFormat VersionFormat
+
+	// Subjects replace the source descriptor as the subjects of the
+	// statement, to attest the tests ran against specific artifacts.
+	Subjects []*v1.ResourceDescriptor
 }
 
 func WithWriter(w io.Writer) OptFn {
@@ -45,3 +187,206 @@ func WithAttest(doattest bool) OptFn {
 		return nil
 	}
 }
+
+// WithTimeout sets the maximum time the tests can run
+func WithTimeout(timeout time.Duration) OptFn {
+	return func(o *Options) error {
+		if timeout < 0 {
+			return fmt.Errorf("invalid timeout %s", timeout)
+		}
+		o.Timeout = timeout
+		return nil
+	}
+}
+
+// WithExitPolicy sets how strictly the runner exit status is reconciled
+// with the parsed results.
+func WithExitPolicy(policy ExitPolicy) OptFn {
+	return func(o *Options) error {
+		if !slices.Contains(ExitPolicies, policy) {
+			return fmt.Errorf("invalid exit policy %q", policy)
+		}
+		o.ExitPolicy = policy
+		return nil
+	}
+}
+
+// WithSigner signs the attestation with signer
+func WithSigner(signer *signing.Signer) OptFn {
+	return func(o *Options) error {
+		o.Signer = signer
+		return nil
+	}
+}
+
+// WithSigningKey signs the attestation with the PEM or GPG private key at
+// path. The passphrase decrypts encrypted PEM and GPG keys.
+func WithSigningKey(path string, passphrase []byte) OptFn {
+	return func(o *Options) error {
+		signer, err := signing.LoadSigner(path, passphrase)
+		if err != nil {
+			return err
+		}
+		o.Signer = signer
+		return nil
+	}
+}
+
+// WithCertificate includes the PEM certificate at path in bundles
+func WithCertificate(path string) OptFn {
+	return func(o *Options) error {
+		cert, err := signing.LoadCertificate(path)
+		if err != nil {
+			return err
+		}
+		o.Certificate = cert
+		return nil
+	}
+}
+
+// WithFormat sets the output format
+func WithFormat(format OutputFormat) OptFn {
+	return func(o *Options) error {
+		if !slices.Contains(OutputFormats, format) {
+			return fmt.Errorf("invalid output format %q", format)
+		}
+		o.Format = format
+		return nil
+	}
+}
+
+// WithLogPath sets the file to write the raw output of the runners to
+func WithLogPath(path string) OptFn {
+	return func(o *Options) error {
+		o.LogPath = path
+		return nil
+	}
+}
+
+// WithConfigFile sets the path of the configuration file to record
+func WithConfigFile(path string) OptFn {
+	return func(o *Options) error {
+		o.ConfigFile = path
+		return nil
+	}
+}
+
+// WithSource sets how the tested sources are identified
+func WithSource(mode SourceMode) OptFn {
+	return func(o *Options) error {
+		if !slices.Contains(SourceModes, mode) {
+			return fmt.Errorf("invalid source mode %q", mode)
+		}
+		o.Source = mode
+		return nil
+	}
+}
+
+// WithSourceIgnore adds patterns of files that are not part of the
+// sources, such as build outputs.
+func WithSourceIgnore(patterns ...string) OptFn {
+	return func(o *Options) error {
+		o.SourceIgnore = append(o.SourceIgnore, patterns...)
+		return nil
+	}
+}
+
+// WithSourceArchive identifies the sources by the digest of the archive
+// at path.
+func WithSourceArchive(path string) OptFn {
+	return func(o *Options) error {
+		if !helpers.Exists(path) {
+			return fmt.Errorf("source archive not found: %q", path)
+		}
+		o.Source = SourceArchive
+		o.SourceArchive = path
+		return nil
+	}
+}
+
+// WithRemote selects the git remote recorded as the repository URL
+func WithRemote(name string) OptFn {
+	return func(o *Options) error {
+		o.Remote = name
+		return nil
+	}
+}
+
+// WithRepoURI overrides the repository URL recorded in the attestation
+func WithRepoURI(uri string) OptFn {
+	return func(o *Options) error {
+		o.RepoURI = uri
+		return nil
+	}
+}
+
+// WithTagPattern sets the glob the git tags must match to compute the
+// version of the sources.
+func WithTagPattern(pattern string) OptFn {
+	return func(o *Options) error {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid tag pattern %q: %w", pattern, err)
+		}
+		o.TagPattern = pattern
+		return nil
+	}
+}
+
+// WithVersionFormat sets the format of the version of commits past the
+// latest tag.
+func WithVersionFormat(format VersionFormat) OptFn {
+	return func(o *Options) error {
+		if !slices.Contains(VersionFormats, format) {
+			return fmt.Errorf("invalid version format %q", format)
+		}
+		o.VersionFormat = format
+		return nil
+	}
+}
+
+// WithRequireClean refuses to attest git working trees with uncommitted
+// changes.
+func WithRequireClean(clean bool) OptFn {
+	return func(o *Options) error {
+		o.RequireClean = clean
+		return nil
+	}
+}
+
+// WithSubject adds a subject in the form name=algorithm:digest
+func WithSubject(spec string) OptFn {
+	return func(o *Options) error {
+		rd, err := ParseSubject(spec)
+		if err != nil {
+			return err
+		}
+		o.Subjects = append(o.Subjects, rd)
+		return nil
+	}
+}
+
+// WithSubjectFile adds a subject with the digest of the file at path
+func WithSubjectFile(path string) OptFn {
+	return func(o *Options) error {
+		rd, err := SubjectFromFile(path)
+		if err != nil {
+			return err
+		}
+		o.Subjects = append(o.Subjects, rd)
+		return nil
+	}
+}
+
+// WithSubjects adds subjects to the statement. Their digests are checked
+// with ValidateDigest.
+func WithSubjects(rds ...*v1.ResourceDescriptor) OptFn {
+	return func(o *Options) error {
+		for _, rd := range rds {
+			if err := ValidateDigest(rd.GetDigest()); err != nil {
+				return fmt.Errorf("subject %q: %w", rd.GetName(), err)
+			}
+		}
+		o.Subjects = append(o.Subjects, rds...)
+		return nil
+	}
+}
@@ -0,0 +1,284 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+package beaker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/carabiner-dev/policy"
+	api "github.com/carabiner-dev/policy/api/v1"
+	"github.com/google/cel-go/cel"
+	v0 "github.com/in-toto/attestation/go/predicates/test_result/v0"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// RuntimeCEL is the tenet runtime supported by beaker, the default of the
+// policy framework.
+const RuntimeCEL = "cel@v0"
+
+// ErrPolicyFailed is returned when the policy did not pass
+var ErrPolicyFailed = errors.New("policy failed")
+
+// structValueType is used to convert the tenet outputs to protobuf values
+var structValueType = reflect.TypeOf(&structpb.Value{})
+
+// LoadPolicy reads a policy file of the carabiner policy framework
+func LoadPolicy(path string) (*api.Policy, error) {
+	p, err := policy.NewParser().ParsePolicyFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	if err := validatePolicy(p); err != nil {
+		return nil, fmt.Errorf("invalid policy %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// ParsePolicy parses a policy of the carabiner policy framework. The
+// tenets are checked to compile.
+func ParsePolicy(data []byte) (*api.Policy, error) {
+	p, err := policy.NewParser().ParsePolicy(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := validatePolicy(p); err != nil {
+		return nil, fmt.Errorf("invalid policy: %w", err)
+	}
+	return p, nil
+}
+
+// validatePolicy checks that beaker can evaluate the policy
+func validatePolicy(p *api.Policy) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	if len(p.GetTenets()) == 0 {
+		return errors.New("policy has no tenets")
+	}
+	switch p.GetMeta().GetAssertMode() {
+	case "", policy.AssertModeAND, policy.AssertModeOR:
+	default:
+		return fmt.Errorf("unknown assert mode %q", p.GetMeta().GetAssertMode())
+	}
+
+	env, err := policyEnv()
+	if err != nil {
+		return err
+	}
+
+	errs := []error{}
+	ids := map[string]struct{}{}
+	for i, t := range p.GetTenets() {
+		if t.GetId() != "" {
+			if _, ok := ids[t.GetId()]; ok {
+				errs = append(errs, fmt.Errorf("tenet #%d: duplicate id %q", i+1, t.GetId()))
+			}
+			ids[t.GetId()] = struct{}{}
+		}
+		if _, _, err := compileTenet(env, p, t); err != nil {
+			errs = append(errs, fmt.Errorf("tenet #%d: %w", i+1, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CheckPolicy evaluates the tenets of a policy against a test result. With
+// the AND assert mode (the default) the policy passes when all its tenets
+// pass, with OR when any of them does. A failed policy that is not
+// enforced is reported as a soft failure. A tenet that fails to evaluate
+// fails with the evaluation error.
+func CheckPolicy(p *api.Policy, result *v0.TestResult) (*api.Result, error) {
+	if p == nil {
+		return nil, errors.New("no policy to check")
+	}
+
+	env, err := policyEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	pctx, err := structpb.NewStruct(p.ContextMap())
+	if err != nil {
+		return nil, fmt.Errorf("reading policy context: %w", err)
+	}
+
+	vars, err := policyVars(result)
+	if err != nil {
+		return nil, err
+	}
+	vars["context"] = pctx.AsMap()
+
+	res := &api.Result{
+		DateStart:   timestamppb.Now(),
+		Policy:      &api.PolicyRef{Id: p.GetId(), Version: p.GetMeta().GetVersion()},
+		Meta:        p.GetMeta(),
+		Context:     pctx,
+		EvalResults: []*api.EvalResult{},
+	}
+
+	passed := 0
+	for i, t := range p.GetTenets() {
+		prg, outputs, err := compileTenet(env, p, t)
+		if err != nil {
+			return nil, fmt.Errorf("tenet #%d: %w", i+1, err)
+		}
+
+		er := evalTenet(prg, outputs, t, vars)
+		if er.GetStatus() == api.StatusPASS {
+			passed++
+		}
+		res.EvalResults = append(res.EvalResults, er)
+	}
+
+	res.Status = api.StatusFAIL
+	switch {
+	case p.GetMeta().GetAssertMode() == policy.AssertModeOR && passed > 0,
+		passed == len(p.GetTenets()):
+		res.Status = api.StatusPASS
+	case p.GetMeta().GetEnforce() == policy.EnforceOff:
+		res.Status = api.StatusSOFTFAIL
+	}
+	res.DateEnd = timestamppb.Now()
+	return res, nil
+}
+
+// policyEnv returns the CEL environment of the tenets. It declares the
+// variables of the cel@v0 runtime of the policy framework engine so
+// policies evaluate the same in beaker and in the engine.
+func policyEnv() (*cel.Env, error) {
+	env, err := cel.NewEnv(
+		cel.Variable("predicates", cel.ListType(cel.DynType)),
+		cel.Variable("predicate", cel.DynType),
+		cel.Variable("context", cel.DynType),
+		cel.Variable("outputs", cel.DynType),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating CEL environment: %w", err)
+	}
+	return env, nil
+}
+
+// compileTenet compiles the code and outputs of a tenet into CEL programs
+func compileTenet(env *cel.Env, p *api.Policy, t *api.Tenet) (cel.Program, map[string]cel.Program, error) {
+	runtime := t.GetRuntime()
+	if runtime == "" {
+		runtime = p.GetMeta().GetRuntime()
+	}
+	if runtime != "" && runtime != RuntimeCEL {
+		return nil, nil, fmt.Errorf("unsupported runtime %q", runtime)
+	}
+
+	for _, spec := range []*api.PredicateSpec{p.GetPredicates(), t.GetPredicates()} {
+		if len(spec.GetTypes()) > 0 && !slices.Contains(spec.GetTypes(), PredicateType) {
+			return nil, nil, fmt.Errorf("predicate types %v do not include %s", spec.GetTypes(), PredicateType)
+		}
+	}
+
+	if t.GetCode() == "" {
+		return nil, nil, errors.New("tenet has no code")
+	}
+	prg, err := compileCode(env, t.GetCode())
+	if err != nil {
+		return nil, nil, fmt.Errorf("compiling tenet code: %w", err)
+	}
+
+	outputs := map[string]cel.Program{}
+	for name, o := range t.GetOutputs() {
+		outputs[name], err = compileCode(env, o.GetCode())
+		if err != nil {
+			return nil, nil, fmt.Errorf("compiling output %q: %w", name, err)
+		}
+	}
+	return prg, outputs, nil
+}
+
+// compileCode compiles a CEL expression into a program
+func compileCode(env *cel.Env, code string) (cel.Program, error) {
+	ast, iss := env.Compile(code)
+	if iss.Err() != nil {
+		return nil, iss.Err()
+	}
+	return env.Program(ast)
+}
+
+// evalTenet runs a compiled tenet and returns its result. The outputs are
+// evaluated first and exposed to the tenet code in outputs.
+func evalTenet(prg cel.Program, outputs map[string]cel.Program, t *api.Tenet, vars map[string]any) *api.EvalResult {
+	er := &api.EvalResult{Id: t.GetId(), Status: api.StatusFAIL, Date: timestamppb.Now()}
+	fail := func(format string, args ...any) *api.EvalResult {
+		er.Error = &api.Error{Message: fmt.Sprintf(format, args...), Guidance: t.GetError().GetGuidance()}
+		return er
+	}
+
+	values := map[string]any{}
+	for name, o := range outputs {
+		out, _, err := o.Eval(vars)
+		if err != nil {
+			return fail("evaluating output %q: %v", name, err)
+		}
+		v, err := out.ConvertToNative(structValueType)
+		if err != nil {
+			return fail("converting output %q: %v", name, err)
+		}
+		if pv, ok := v.(*structpb.Value); ok {
+			values[name] = pv.AsInterface()
+		}
+	}
+	output, err := structpb.NewStruct(values)
+	if err != nil {
+		return fail("recording outputs: %v", err)
+	}
+	er.Output = output
+
+	tvars := map[string]any{"outputs": values}
+	for k, v := range vars {
+		tvars[k] = v
+	}
+	out, _, err := prg.Eval(tvars)
+	if err != nil {
+		return fail("evaluating tenet: %v", err)
+	}
+
+	pass, ok := out.Value().(bool)
+	if !ok {
+		return fail("tenet evaluated to %s instead of a boolean", out.Type().TypeName())
+	}
+	if !pass {
+		er.Error = t.GetError()
+		return er
+	}
+	er.Status = api.StatusPASS
+	er.Assessment = t.GetAssessment()
+	return er
+}
+
+// policyVars returns the variables exposed to the tenets. As in the
+// engine, the predicate is read from its JSON encoding under data, so
+// tenets test for presence before reading fields that may be empty.
+func policyVars(result *v0.TestResult) (map[string]any, error) {
+	if result == nil {
+		result = &v0.TestResult{}
+	}
+
+	data, err := protojson.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling predicate: %w", err)
+	}
+	fields := map[string]any{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("decoding predicate: %w", err)
+	}
+
+	predicate := map[string]any{"predicate_type": PredicateType, "data": fields}
+	return map[string]any{
+		"predicates": []any{predicate},
+		"predicate":  predicate,
+	}, nil
+}
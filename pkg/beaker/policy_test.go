@@ -0,0 +1,154 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+package beaker
+
+import (
+	"testing"
+
+	api "github.com/carabiner-dev/policy/api/v1"
+	v0 "github.com/in-toto/attestation/go/predicates/test_result/v0"
+	"github.com/stretchr/testify/require"
+
+	"github.com/carabiner-dev/beaker/models"
+)
+
+func TestParsePolicy(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name    string
+		data    string
+		mustErr bool
+	}{
+		{"json", `{"id": "p", "tenets": [{"id": "t", "code": "predicate.data.result == 'pass'"}]}`, false},
+		{"hjson", "{\n  id: p\n  tenets: [\n    {\n      id: t\n      code: !has(predicate.data.failedTests)\n    }\n  ]\n}\n", false},
+		{"predicates", `{"id": "p", "tenets": [{"id": "t", "code": "predicates.all(p, p.data.result == 'pass')"}]}`, false},
+		{"runtime", `{"id": "p", "meta": {"runtime": "cel@v0"}, "tenets": [{"id": "t", "code": "true"}]}`, false},
+		{"predicate-type", `{"id": "p", "predicates": {"types": ["https://in-toto.io/attestation/test-result/v0.1"]}, "tenets": [{"code": "true"}]}`, false},
+		{"no-tenets", `{"id": "p", "tenets": []}`, true},
+		{"unknown-key", `{"id": "p", "rules": [], "tenets": [{"code": "true"}]}`, true},
+		{"no-code", `{"id": "p", "tenets": [{"id": "t"}]}`, true},
+		{"bad-code", `{"id": "p", "tenets": [{"id": "t", "code": "predicate.data.result =="}]}`, true},
+		{"undeclared", `{"id": "p", "tenets": [{"id": "t", "code": "size(details.skipped) == 0"}]}`, true},
+		{"bad-output", `{"id": "p", "tenets": [{"code": "true", "outputs": {"o": {"code": "=="}}}]}`, true},
+		{"duplicate-id", `{"id": "p", "tenets": [{"id": "t", "code": "true"}, {"id": "t", "code": "false"}]}`, true},
+		{"other-runtime", `{"id": "p", "tenets": [{"id": "t", "runtime": "rego@v1", "code": "true"}]}`, true},
+		{"other-predicate", `{"id": "p", "tenets": [{"id": "t", "code": "true", "predicates": {"types": ["https://slsa.dev/provenance/v1"]}}]}`, true},
+		{"assert-mode", `{"id": "p", "meta": {"assertMode": "XOR"}, "tenets": [{"code": "true"}]}`, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			p, err := ParsePolicy([]byte(tc.data))
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, p.GetTenets(), 1)
+		})
+	}
+}
+
+func TestCheckPolicy(t *testing.T) {
+	t.Parallel()
+	result := &v0.TestResult{
+		Result:      resultFail,
+		PassedTests: []string{"TestA", "TestB", "TestC"},
+		FailedTests: []string{"example.com/flaky [build failed]"},
+	}
+	require.NoError(t, models.SetTestDetails(result, &models.TestDetails{Skipped: []string{"TestD"}}))
+
+	for _, tc := range []struct {
+		name     string
+		code     string
+		expected string
+	}{
+		{"min-tests", "size(predicate.data.passedTests) + size(predicate.data.failedTests) >= 4", api.StatusPASS},
+		{"too-few-tests", "size(predicate.data.passedTests) >= 10", api.StatusFAIL},
+		{"package", "!predicate.data.failedTests.exists(t, t.startsWith('example.com/flaky'))", api.StatusFAIL},
+		{"other-package", "!predicate.data.failedTests.exists(t, t.startsWith('example.com/stable'))", api.StatusPASS},
+		{"predicate-type", "predicates.size() == 1 && predicate.predicate_type == '" + PredicateType + "'", api.StatusPASS},
+		{"unpopulated", "!has(predicate.data.warnedTests)", api.StatusPASS},
+		{"skipped", "predicate.data.configuration.exists(c, c.name == 'beaker-test-details' && size(c.annotations.skipped) == 1)", api.StatusPASS},
+		{"context", "size(predicate.data.passedTests) >= context.minTests", api.StatusPASS},
+		{"outputs", "outputs.passed == 3", api.StatusPASS},
+		{"not-bool", "predicate.data.result", api.StatusFAIL},
+		{"eval-error", "size(predicate.data.warnedTests) == 0", api.StatusFAIL},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			p, err := ParsePolicy([]byte(`{
+				"id": "test",
+				"context": {"minTests": {"type": "int", "default": 3}},
+				"tenets": [{
+					"id": "` + tc.name + `",
+					"code": "` + tc.code + `",
+					"outputs": {"passed": {"code": "size(predicate.data.passedTests)"}},
+					"assessment": {"message": "ok"},
+					"error": {"message": "not ok", "guidance": "fix it"}
+				}]
+			}`))
+			require.NoError(t, err)
+
+			res, err := CheckPolicy(p, result)
+			require.NoError(t, err)
+			require.Equal(t, "test", res.GetPolicy().GetId())
+			require.Equal(t, tc.expected, res.GetStatus())
+			require.Len(t, res.GetEvalResults(), 1)
+			er := res.GetEvalResults()[0]
+			require.Equal(t, tc.expected, er.GetStatus())
+			require.Equal(t, tc.name, er.GetId())
+			switch {
+			case tc.expected == api.StatusPASS:
+				require.Equal(t, "ok", er.GetAssessment().GetMessage())
+				require.Nil(t, er.GetError())
+				require.InDelta(t, 3, er.GetOutput().GetFields()["passed"].GetNumberValue(), 0)
+			case tc.name == "not-bool", tc.name == "eval-error":
+				require.NotEqual(t, "not ok", er.GetError().GetMessage())
+				require.Equal(t, "fix it", er.GetError().GetGuidance())
+			default:
+				require.Equal(t, "not ok", er.GetError().GetMessage())
+				require.Equal(t, "fix it", er.GetError().GetGuidance())
+			}
+		})
+	}
+}
+
+func TestCheckPolicyModes(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name     string
+		meta     string
+		expected string
+	}{
+		{"and", `{"assertMode": "AND"}`, api.StatusFAIL},
+		{"default", `{}`, api.StatusFAIL},
+		{"or", `{"assertMode": "OR"}`, api.StatusPASS},
+		{"not-enforced", `{"enforce": "OFF"}`, api.StatusSOFTFAIL},
+		{"enforced", `{"enforce": "ON"}`, api.StatusFAIL},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			p, err := ParsePolicy([]byte(`{"id": "p", "meta": ` + tc.meta + `, "tenets": [
+				{"id": "pass", "code": "true"},
+				{"id": "fail", "code": "false"}
+			]}`))
+			require.NoError(t, err)
+			res, err := CheckPolicy(p, &v0.TestResult{Result: resultPass})
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, res.GetStatus())
+		})
+	}
+}
+
+func TestCheckFrameworkPolicy(t *testing.T) {
+	t.Parallel()
+	// policy.single.json is a fixture of the carabiner-dev/policy module
+	p, err := LoadPolicy("testdata/policy.single.json")
+	require.NoError(t, err)
+	res, err := CheckPolicy(p, &v0.TestResult{Result: resultPass})
+	require.NoError(t, err)
+	require.Equal(t, "policy-assert-mode-test", res.GetPolicy().GetId())
+	require.Equal(t, api.StatusPASS, res.GetStatus())
+	require.Len(t, res.GetEvalResults(), 2)
+}
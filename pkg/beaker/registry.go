@@ -0,0 +1,163 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+package beaker
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/carabiner-dev/beaker/models"
+)
+
+// DetectFunc reports if a runner can handle the codebase at path.
+type DetectFunc func(path string) bool
+
+// RunnerFactory creates a launch pack to run the tests of the codebase at
+// path. The runner configuration is never nil but its fields may be empty.
+type RunnerFactory func(path string, conf *RunnerConfig) (*LaunchPack, error)
+
+// ParserFactory creates a results parser for the codebase at path. The
+// runner configuration is never nil but its fields may be empty.
+type ParserFactory func(path string, conf *RunnerConfig) (models.ResultsParser, error)
+
+// RunnerDefinition describes a runner that can be registered in beaker.
+type RunnerDefinition struct {
+	// Name is the name used to select the runner in flags and config files.
+	Name string
+
+	// Detect reports if the runner applies to a codebase. Runners without
+	// a detection function are only used when selected explicitly.
+	Detect DetectFunc
+
+	// New builds the launch pack for the runner.
+	New RunnerFactory
+}
+
+// registry holds the runners and parsers known to beaker. Runners are kept
+// in registration order which is also the order used for detection.
+type registry struct {
+	sync.RWMutex
+	runners []RunnerDefinition
+	parsers map[string]ParserFactory
+}
+
+var defaultRegistry = &registry{
+	runners: []RunnerDefinition{},
+	parsers: map[string]ParserFactory{},
+}
+
+// RegisterRunner adds a runner to beaker. Programs embedding beaker can
+// call it to add their own runners. Registering a name twice is an error.
+func RegisterRunner(def RunnerDefinition) error {
+	if def.Name == "" {
+		return errors.New("runner definition has no name")
+	}
+	if def.New == nil {
+		return fmt.Errorf("runner %q has no constructor", def.Name)
+	}
+
+	defaultRegistry.Lock()
+	defer defaultRegistry.Unlock()
+	for _, r := range defaultRegistry.runners {
+		if r.Name == def.Name {
+			return fmt.Errorf("runner %q is already registered", def.Name)
+		}
+	}
+	defaultRegistry.runners = append(defaultRegistry.runners, def)
+	return nil
+}
+
+// RegisterParser adds a results parser that can be paired with runners
+// in the configuration file.
+func RegisterParser(name string, factory ParserFactory) error {
+	if name == "" {
+		return errors.New("parser has no name")
+	}
+	if factory == nil {
+		return fmt.Errorf("parser %q has no constructor", name)
+	}
+
+	defaultRegistry.Lock()
+	defer defaultRegistry.Unlock()
+	if _, ok := defaultRegistry.parsers[name]; ok {
+		return fmt.Errorf("parser %q is already registered", name)
+	}
+	defaultRegistry.parsers[name] = factory
+	return nil
+}
+
+// mustRegisterRunner registers a built-in runner, panicking on error
+func mustRegisterRunner(def RunnerDefinition) {
+	if err := RegisterRunner(def); err != nil {
+		panic(err)
+	}
+}
+
+// mustRegisterParser registers a built-in parser, panicking on error
+func mustRegisterParser(name string, factory ParserFactory) {
+	if err := RegisterParser(name, factory); err != nil {
+		panic(err)
+	}
+}
+
+// Runners returns the names of the registered runners
+func Runners() []string {
+	defaultRegistry.RLock()
+	defer defaultRegistry.RUnlock()
+	names := make([]string, 0, len(defaultRegistry.runners))
+	for _, r := range defaultRegistry.runners {
+		names = append(names, r.Name)
+	}
+	return names
+}
+
+// Parsers returns the names of the registered parsers, sorted
+func Parsers() []string {
+	defaultRegistry.RLock()
+	defer defaultRegistry.RUnlock()
+	names := make([]string, 0, len(defaultRegistry.parsers))
+	for name := range defaultRegistry.parsers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// getRunner returns the runner definition registered under name
+func getRunner(name string) (RunnerDefinition, bool) {
+	defaultRegistry.RLock()
+	defer defaultRegistry.RUnlock()
+	for _, r := range defaultRegistry.runners {
+		if r.Name == name {
+			return r, true
+		}
+	}
+	return RunnerDefinition{}, false
+}
+
+// getParser returns the parser factory registered under name
+func getParser(name string) (ParserFactory, bool) {
+	defaultRegistry.RLock()
+	defer defaultRegistry.RUnlock()
+	f, ok := defaultRegistry.parsers[name]
+	return f, ok
+}
+
+// detectRunner returns the first registered runner that detects the
+// codebase at path. The detection functions run without holding the
+// registry lock as they may be slow or register runners themselves.
+func detectRunner(path string) (RunnerDefinition, bool) {
+	defaultRegistry.RLock()
+	runners := slices.Clone(defaultRegistry.runners)
+	defaultRegistry.RUnlock()
+
+	for _, r := range runners {
+		if r.Detect != nil && r.Detect(path) {
+			return r, true
+		}
+	}
+	return RunnerDefinition{}, false
+}
@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+package beaker
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/carabiner-dev/beaker/models"
+	"github.com/carabiner-dev/beaker/pkg/runners/shell"
+)
+
+// testRunnerFactory returns a runner factory that builds a shell runner
+func testRunnerFactory(path string, conf *RunnerConfig) (*LaunchPack, error) {
+	r, err := shell.New(shell.WithWorkDir(path), shell.WithCommand("true"))
+	if err != nil {
+		return nil, err
+	}
+	return &LaunchPack{Runner: r}, nil
+}
+
+// pathDetector returns a detection function matching only path
+func pathDetector(path string) DetectFunc {
+	return func(p string) bool { return p == path }
+}
+
+func TestRegisterRunner(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name    string
+		def     RunnerDefinition
+		mustErr bool
+	}{
+		{"valid", RunnerDefinition{Name: "test-register-valid", New: testRunnerFactory}, false},
+		{"no-name", RunnerDefinition{New: testRunnerFactory}, true},
+		{"no-constructor", RunnerDefinition{Name: "test-register-no-constructor"}, true},
+		{"builtin-name", RunnerDefinition{Name: runnerGolang, New: testRunnerFactory}, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := RegisterRunner(tc.def)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Contains(t, Runners(), tc.def.Name)
+
+			// The same name cannot be registered twice
+			require.Error(t, RegisterRunner(tc.def))
+		})
+	}
+}
+
+func TestRegisterParser(t *testing.T) {
+	t.Parallel()
+	factory := func(string, *RunnerConfig) (models.ResultsParser, error) { return nil, nil } //nolint:nilnil
+	for _, tc := range []struct {
+		name    string
+		parser  string
+		factory ParserFactory
+		mustErr bool
+	}{
+		{"valid", "test-parser-valid", factory, false},
+		{"no-name", "", factory, true},
+		{"no-constructor", "test-parser-no-constructor", nil, true},
+		{"builtin-name", parserJUnit, factory, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := RegisterParser(tc.parser, tc.factory)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Contains(t, Parsers(), tc.parser)
+			require.True(t, slices.IsSorted(Parsers()))
+			require.Error(t, RegisterParser(tc.parser, tc.factory))
+		})
+	}
+}
+
+func TestDetectRunner(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	// Runners are detected in registration order
+	require.NoError(t, RegisterRunner(RunnerDefinition{Name: "test-detect-manual", New: testRunnerFactory}))
+	require.NoError(t, RegisterRunner(RunnerDefinition{Name: "test-detect-first", Detect: pathDetector(dir), New: testRunnerFactory}))
+	require.NoError(t, RegisterRunner(RunnerDefinition{Name: "test-detect-second", Detect: pathDetector(dir), New: testRunnerFactory}))
+
+	def, ok := detectRunner(dir)
+	require.True(t, ok)
+	require.Equal(t, "test-detect-first", def.Name)
+
+	_, ok = detectRunner(t.TempDir())
+	require.False(t, ok)
+
+	// Detection functions can write to the registry without deadlocking
+	other := t.TempDir()
+	reentrant := RunnerDefinition{Name: "test-detect-reentrant", New: testRunnerFactory}
+	reentrant.Detect = func(p string) bool {
+		return p == other && RegisterRunner(reentrant) != nil
+	}
+	require.NoError(t, RegisterRunner(reentrant))
+	def, ok = detectRunner(other)
+	require.True(t, ok)
+	require.Equal(t, "test-detect-reentrant", def.Name)
+}
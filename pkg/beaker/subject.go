@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+package beaker
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	v1 "github.com/in-toto/attestation/go/v1"
+
+	"github.com/carabiner-dev/beaker/models"
+)
+
+// digestLengths maps the digest algorithms accepted in custom subjects to
+// the length of their hex encoded values.
+var digestLengths = map[string]int{
+	"sha1":      40,
+	"sha224":    56,
+	"sha256":    64,
+	"sha384":    96,
+	"sha512":    128,
+	"gitCommit": 40,
+}
+
+// ParseSubject parses a subject in the form name=algorithm:digest, such as
+// beaker=sha256:4d5e... The digest is checked with ValidateDigest.
+func ParseSubject(spec string) (*v1.ResourceDescriptor, error) {
+	name, digest, ok := cutLast(spec, "=")
+	if !ok || name == "" {
+		return nil, fmt.Errorf("invalid subject %q, expected name=algorithm:digest", spec)
+	}
+
+	algo, value, ok := strings.Cut(digest, ":")
+	if !ok {
+		return nil, fmt.Errorf("invalid subject digest %q, expected algorithm:digest", digest)
+	}
+
+	rd := &v1.ResourceDescriptor{
+		Name:   name,
+		Digest: map[string]string{algo: strings.ToLower(value)},
+	}
+	if err := ValidateDigest(rd.GetDigest()); err != nil {
+		return nil, fmt.Errorf("subject %q: %w", name, err)
+	}
+	return rd, nil
+}
+
+// SubjectFromFile returns a subject named after the file at path with its
+// sha256 digest.
+func SubjectFromFile(path string) (*v1.ResourceDescriptor, error) {
+	rd, err := models.FileDescriptor(path, filepath.Base(path))
+	if err != nil {
+		return nil, fmt.Errorf("hashing subject file: %w", err)
+	}
+	return rd, nil
+}
+
+// ValidateDigest checks that a digest set is not empty and that its
+// values are lowercase hex strings of the right length for their
+// algorithm.
+func ValidateDigest(digest map[string]string) error {
+	if len(digest) == 0 {
+		return errors.New("no digest set")
+	}
+
+	errs := []error{}
+	for _, algo := range slices.Sorted(maps.Keys(digest)) {
+		value := digest[algo]
+		length, ok := digestLengths[algo]
+		if !ok {
+			errs = append(errs, fmt.Errorf("unsupported digest algorithm %q", algo))
+			continue
+		}
+		if len(value) != length {
+			errs = append(errs, fmt.Errorf("%s digest must be %d hex characters long, got %d", algo, length, len(value)))
+			continue
+		}
+		if strings.Trim(value, "0123456789abcdef") != "" {
+			errs = append(errs, fmt.Errorf("%s digest is not a lowercase hex string", algo))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// cutLast slices s around the last instance of sep
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+package beaker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSubject(t *testing.T) {
+	t.Parallel()
+	digest := strings.Repeat("ab", 32)
+	for _, tc := range []struct {
+		name    string
+		spec    string
+		subject string
+		digest  map[string]string
+		mustErr bool
+	}{
+		{"sha256", "beaker=sha256:" + digest, "beaker", map[string]string{"sha256": digest}, false},
+		{"uppercase", "beaker=sha256:" + strings.ToUpper(digest), "beaker", map[string]string{"sha256": digest}, false},
+		{"name-with-equals", "a=b=sha1:" + testCommit, "a=b", map[string]string{"sha1": testCommit}, false},
+		{"git-commit", "repo=gitCommit:" + testCommit, "repo", map[string]string{"gitCommit": testCommit}, false},
+		{"no-name", "=sha256:" + digest, "", nil, true},
+		{"no-digest", "beaker", "", nil, true},
+		{"no-algorithm", "beaker=" + digest, "", nil, true},
+		{"unknown-algorithm", "beaker=md5:" + digest[:32], "", nil, true},
+		{"short", "beaker=sha256:" + digest[:63], "", nil, true},
+		{"long", "beaker=sha1:" + digest, "", nil, true},
+		{"not-hex", "beaker=sha256:" + strings.Repeat("zz", 32), "", nil, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			rd, err := ParseSubject(tc.spec)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.subject, rd.GetName())
+			require.Equal(t, tc.digest, rd.GetDigest())
+		})
+	}
+}
+
+func TestSubjectOptions(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "beaker-linux-amd64")
+	require.NoError(t, os.WriteFile(path, []byte("archive data"), 0o600))
+
+	digest := strings.Repeat("01", 32)
+	launcher, err := New(
+		WithSubject("image=sha256:"+digest),
+		WithSubjectFile(path),
+	)
+	require.NoError(t, err)
+	require.Len(t, launcher.Options.Subjects, 2)
+	require.Equal(t, "image", launcher.Options.Subjects[0].GetName())
+	require.Equal(t, "beaker-linux-amd64", launcher.Options.Subjects[1].GetName())
+	sum := sha256.Sum256([]byte("archive data"))
+	require.Equal(t, hex.EncodeToString(sum[:]), launcher.Options.Subjects[1].GetDigest()["sha256"])
+
+	_, err = New(WithSubjectFile(filepath.Join(t.TempDir(), "missing")))
+	require.Error(t, err)
+}
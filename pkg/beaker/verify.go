@@ -0,0 +1,221 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+package beaker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	v0 "github.com/in-toto/attestation/go/predicates/test_result/v0"
+	v1 "github.com/in-toto/attestation/go/v1"
+	sdsse "github.com/sigstore/protobuf-specs/gen/pb-go/dsse"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/carabiner-dev/beaker/pkg/signing"
+)
+
+// PredicateType is the type of the test-result predicates written by beaker
+const PredicateType = "https://in-toto.io/attestation/test-result/v0.1"
+
+var (
+	// ErrSignature is returned when the attestation signature does not
+	// verify with the key, or the attestation is not signed.
+	ErrSignature = errors.New("signature verification failed")
+
+	// ErrPredicateType is returned when the statement does not carry a
+	// test-result predicate.
+	ErrPredicateType = errors.New("wrong predicate type")
+
+	// ErrSubject is returned when no subject matches the expected commit
+	ErrSubject = errors.New("subject does not match")
+
+	// ErrDirtySubject is returned when the subject matching the expected
+	// commit was tested with uncommitted changes in the working tree.
+	ErrDirtySubject = errors.New("subject has uncommitted changes")
+
+	// ErrTestsFailed is returned when the attested result is not a pass
+	ErrTestsFailed = errors.New("tests did not pass")
+)
+
+// VerifyOptions control the checks performed on an attestation
+type VerifyOptions struct {
+	// Verifier checks the DSSE signature. It is required unless
+	// SkipSignature is set.
+	Verifier *signing.Verifier
+
+	// SkipSignature accepts unsigned attestations and envelopes without
+	// checking their signature when no verifier is set.
+	SkipSignature bool
+
+	// Commit is the git commit expected in the statement subjects. Empty
+	// skips the subject check.
+	Commit string
+
+	// Subject, when set, is the repository subject expected in the
+	// statement, as returned by RepoSubject. Besides its commit, the
+	// version and URI of the matching subject must be the same.
+	Subject *v1.ResourceDescriptor
+}
+
+// Verification holds the contents of a verified attestation
+type Verification struct {
+	Statement *v1.Statement
+	Predicate *v0.TestResult
+
+	// Signed is true when the envelope signature was verified
+	Signed bool
+}
+
+// envelopeFields are used to detect if the data is a DSSE envelope or a
+// sigstore bundle.
+type envelopeFields struct {
+	PayloadType string `json:"payloadType"`
+	MediaType   string `json:"mediaType"`
+}
+
+// Verify checks an attestation produced by beaker, either a statement, a
+// DSSE envelope wrapping one or a sigstore bundle with the envelope. The
+// returned errors wrap ErrSignature, ErrPredicateType, ErrSubject,
+// ErrDirtySubject or ErrTestsFailed to tell the failed check apart. The
+// verification is returned along ErrTestsFailed.
+func Verify(_ context.Context, data []byte, opts *VerifyOptions) (*Verification, error) {
+	if opts == nil {
+		opts = &VerifyOptions{}
+	}
+	if opts.Verifier == nil && !opts.SkipSignature {
+		return nil, fmt.Errorf("%w: no key to verify the signature", ErrSignature)
+	}
+
+	payload, signed, err := verifyEnvelope(data, opts.Verifier)
+	if err != nil {
+		return nil, err
+	}
+
+	statement := &v1.Statement{}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(payload, statement); err != nil {
+		return nil, fmt.Errorf("parsing statement: %w", err)
+	}
+	if statement.GetPredicateType() != PredicateType {
+		return nil, fmt.Errorf("%w: %q", ErrPredicateType, statement.GetPredicateType())
+	}
+
+	predicate := &v0.TestResult{}
+	pdata, err := protojson.Marshal(statement.GetPredicate())
+	if err != nil {
+		return nil, fmt.Errorf("marshaling predicate: %w", err)
+	}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(pdata, predicate); err != nil {
+		return nil, fmt.Errorf("parsing predicate: %w", err)
+	}
+
+	if err := checkSubject(statement.GetSubject(), opts); err != nil {
+		return nil, err
+	}
+
+	v := &Verification{Statement: statement, Predicate: predicate, Signed: signed}
+	if predicate.GetResult() != resultPass {
+		return v, fmt.Errorf("%w: result is %q", ErrTestsFailed, predicate.GetResult())
+	}
+	return v, nil
+}
+
+// verifyEnvelope returns the statement in data. If data is a DSSE
+// envelope or a bundle, its signature is verified when a verifier is set.
+func verifyEnvelope(data []byte, verifier *signing.Verifier) (payload []byte, signed bool, err error) {
+	fields := &envelopeFields{}
+	if err := json.Unmarshal(data, fields); err != nil {
+		return nil, false, fmt.Errorf("parsing attestation: %w", err)
+	}
+
+	env := &sdsse.Envelope{}
+	switch {
+	case strings.HasPrefix(fields.MediaType, "application/vnd.dev.sigstore.bundle"):
+		env, err = signing.ParseBundle(data)
+		if err != nil {
+			return nil, false, err
+		}
+	case fields.PayloadType != "":
+		if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, env); err != nil {
+			return nil, false, fmt.Errorf("parsing envelope: %w", err)
+		}
+	default:
+		// Not an envelope, a plain statement
+		if verifier != nil {
+			return nil, false, fmt.Errorf("%w: attestation is not signed", ErrSignature)
+		}
+		return data, false, nil
+	}
+
+	if env.GetPayloadType() != signing.PayloadType {
+		return nil, false, fmt.Errorf("unsupported envelope payload type %q", env.GetPayloadType())
+	}
+
+	if verifier == nil {
+		return env.GetPayload(), false, nil
+	}
+
+	if err := verifier.Verify(env); err != nil {
+		return nil, false, fmt.Errorf("%w: %w", ErrSignature, err)
+	}
+	return env.GetPayload(), true, nil
+}
+
+// checkSubject looks for the subject expected by the options in the
+// statement subjects.
+func checkSubject(subjects []*v1.ResourceDescriptor, opts *VerifyOptions) error {
+	commit := opts.Commit
+	if opts.Subject != nil {
+		commit = opts.Subject.GetDigest()["gitCommit"]
+	}
+	if commit == "" {
+		return nil
+	}
+
+	subject := subjectForCommit(subjects, commit)
+	if subject == nil {
+		return fmt.Errorf("%w: no subject with commit %s", ErrSubject, commit)
+	}
+
+	if subjectIsDirty(subject) {
+		return fmt.Errorf("%w: %s was tested on a dirty working tree", ErrDirtySubject, subject.GetName())
+	}
+
+	if opts.Subject != nil {
+		if subject.GetName() != opts.Subject.GetName() {
+			return fmt.Errorf("%w: subject version %q is not %q", ErrSubject, subject.GetName(), opts.Subject.GetName())
+		}
+		if subject.GetUri() != opts.Subject.GetUri() {
+			return fmt.Errorf("%w: subject URI %q is not %q", ErrSubject, subject.GetUri(), opts.Subject.GetUri())
+		}
+	}
+	return nil
+}
+
+// subjectForCommit returns the subject with the commit in its gitCommit or
+// sha1 digests. Clean subjects are preferred over dirty ones.
+func subjectForCommit(subjects []*v1.ResourceDescriptor, commit string) *v1.ResourceDescriptor {
+	var match *v1.ResourceDescriptor
+	for _, s := range subjects {
+		for _, algo := range []string{"gitCommit", "sha1"} {
+			d, ok := s.GetDigest()[algo]
+			if !ok || !strings.EqualFold(d, commit) {
+				continue
+			}
+			if !subjectIsDirty(s) {
+				return s
+			}
+			match = s
+		}
+	}
+	return match
+}
+
+// subjectIsDirty returns true if the subject carries the dirty annotation
+// added by markDirty.
+func subjectIsDirty(rd *v1.ResourceDescriptor) bool {
+	return rd.GetAnnotations().GetFields()["dirty"].GetBoolValue()
+}
@@ -0,0 +1,145 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+package beaker
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/carabiner-dev/signer/key"
+	v1 "github.com/in-toto/attestation/go/v1"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/carabiner-dev/beaker/pkg/signing"
+)
+
+const testCommit = "0123456789abcdef0123456789abcdef01234567"
+
+// testStatement returns a test-result statement for the tests
+func testStatement(t *testing.T, predicateType, result string) []byte {
+	t.Helper()
+	return testStatementWithSubject(t, predicateType, result, &v1.ResourceDescriptor{
+		Uri: "git+https://example.com/repo", Digest: map[string]string{"gitCommit": testCommit},
+	})
+}
+
+// testStatementWithSubject returns a test-result statement about subject
+func testStatementWithSubject(t *testing.T, predicateType, result string, subject *v1.ResourceDescriptor) []byte {
+	t.Helper()
+	predicate, err := structpb.NewStruct(map[string]any{
+		"result":      result,
+		"passedTests": []any{"TestA"},
+	})
+	require.NoError(t, err)
+	data, err := protojson.Marshal(&v1.Statement{
+		Type:          v1.StatementTypeUri,
+		Subject:       []*v1.ResourceDescriptor{subject},
+		PredicateType: predicateType,
+		Predicate:     predicate,
+	})
+	require.NoError(t, err)
+	return data
+}
+
+// testSigner returns a signer with an ephemeral key
+func testSigner(t *testing.T) *signing.Signer {
+	t.Helper()
+	priv, err := key.NewGenerator().GenerateKeyPair()
+	require.NoError(t, err)
+	signer, err := signing.NewSigner(priv)
+	require.NoError(t, err)
+	return signer
+}
+
+// testVerifier returns a verifier for the key of signer
+func testVerifier(t *testing.T, signer *signing.Signer) *signing.Verifier {
+	t.Helper()
+	pub, err := signer.PublicKey()
+	require.NoError(t, err)
+	verifier, err := signing.NewVerifier(pub)
+	require.NoError(t, err)
+	return verifier
+}
+
+// signBundle wraps a statement in a signed sigstore bundle
+func signBundle(t *testing.T, signer *signing.Signer, statement []byte) []byte {
+	t.Helper()
+	env, err := signer.SignPayload(signing.PayloadType, statement)
+	require.NoError(t, err)
+	bundle, err := signing.NewBundle(env, signer, nil)
+	require.NoError(t, err)
+	data, err := protojson.Marshal(bundle)
+	require.NoError(t, err)
+	return data
+}
+
+// sign wraps a statement in a DSSE envelope
+func sign(t *testing.T, signer *signing.Signer, statement []byte) []byte {
+	t.Helper()
+	env, err := signer.SignPayload(signing.PayloadType, statement)
+	require.NoError(t, err)
+	data, err := protojson.Marshal(env)
+	require.NoError(t, err)
+	return data
+}
+
+// repoSubject returns a subject as built by RepoSubject
+func repoSubject(name, uri, commit string) *v1.ResourceDescriptor {
+	return &v1.ResourceDescriptor{Name: name, Uri: uri, Digest: map[string]string{"sha1": commit, "gitCommit": commit}}
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+	signer := testSigner(t)
+	verifier := testVerifier(t, signer)
+	other := testVerifier(t, testSigner(t))
+	annotations, err := structpb.NewStruct(map[string]any{"dirty": true})
+	require.NoError(t, err)
+	dirty := testStatementWithSubject(t, PredicateType, resultPass, &v1.ResourceDescriptor{
+		Name: "repo@v1.0.0-dirty", Digest: map[string]string{"gitCommit": testCommit}, Annotations: annotations,
+	})
+
+	for _, tc := range []struct {
+		name     string
+		data     []byte
+		opts     *VerifyOptions
+		expected error
+		signed   bool
+	}{
+		{"signed", sign(t, signer, testStatement(t, PredicateType, resultPass)), &VerifyOptions{Verifier: verifier, Commit: testCommit}, nil, true},
+		{"bundle", signBundle(t, signer, testStatement(t, PredicateType, resultPass)), &VerifyOptions{Verifier: verifier}, nil, true},
+		{"bundle-wrong-key", signBundle(t, signer, testStatement(t, PredicateType, resultPass)), &VerifyOptions{Verifier: other}, ErrSignature, false},
+		{"no-key", sign(t, signer, testStatement(t, PredicateType, resultPass)), nil, ErrSignature, false},
+		{"unsigned-skip", testStatement(t, PredicateType, resultPass), &VerifyOptions{SkipSignature: true}, nil, false},
+		{"envelope-skip", sign(t, signer, testStatement(t, PredicateType, resultPass)), &VerifyOptions{SkipSignature: true}, nil, false},
+		{"wrong-key", sign(t, signer, testStatement(t, PredicateType, resultPass)), &VerifyOptions{Verifier: other}, ErrSignature, false},
+		{"unsigned-with-key", testStatement(t, PredicateType, resultPass), &VerifyOptions{Verifier: verifier}, ErrSignature, false},
+		{"predicate-type", testStatement(t, "https://slsa.dev/provenance/v1", resultPass), &VerifyOptions{SkipSignature: true}, ErrPredicateType, false},
+		{"wrong-subject", testStatement(t, PredicateType, resultPass), &VerifyOptions{SkipSignature: true, Commit: "fedcba9876543210fedcba9876543210fedcba98"}, ErrSubject, false},
+		{"dirty", sign(t, signer, dirty), &VerifyOptions{Verifier: verifier, Commit: testCommit}, ErrDirtySubject, false},
+		{"dirty-no-commit", sign(t, signer, dirty), &VerifyOptions{Verifier: verifier}, nil, true},
+		{"repo-subject", sign(t, signer, testStatement(t, PredicateType, resultPass)), &VerifyOptions{Verifier: verifier, Subject: repoSubject("", "git+https://example.com/repo", testCommit)}, nil, true},
+		{"repo-subject-version", sign(t, signer, testStatement(t, PredicateType, resultPass)), &VerifyOptions{Verifier: verifier, Subject: repoSubject("v1.0.0", "git+https://example.com/repo", testCommit)}, ErrSubject, false},
+		{"repo-subject-uri", sign(t, signer, testStatement(t, PredicateType, resultPass)), &VerifyOptions{Verifier: verifier, Subject: repoSubject("", "git+https://example.com/fork", testCommit)}, ErrSubject, false},
+		{"repo-subject-commit", sign(t, signer, testStatement(t, PredicateType, resultPass)), &VerifyOptions{Verifier: verifier, Subject: repoSubject("", "git+https://example.com/repo", "fedcba9876543210fedcba9876543210fedcba98")}, ErrSubject, false},
+		{"repo-subject-dirty", sign(t, signer, dirty), &VerifyOptions{Verifier: verifier, Subject: repoSubject("repo@v1.0.0", "", testCommit)}, ErrDirtySubject, false},
+		{"failed", sign(t, signer, testStatement(t, PredicateType, resultFail)), &VerifyOptions{Verifier: verifier}, ErrTestsFailed, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			v, err := Verify(t.Context(), tc.data, tc.opts)
+			if tc.expected != nil {
+				require.ErrorIs(t, err, tc.expected)
+			} else {
+				require.NoError(t, err)
+			}
+			if err == nil || errors.Is(tc.expected, ErrTestsFailed) {
+				require.Equal(t, tc.signed, v.Signed)
+				require.Equal(t, []string{"TestA"}, v.Predicate.GetPassedTests())
+			}
+		})
+	}
+}
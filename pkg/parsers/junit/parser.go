@@ -0,0 +1,334 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+package junit
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	testresult "github.com/in-toto/attestation/go/predicates/test_result/v0"
+	intoto "github.com/in-toto/attestation/go/v1"
+
+	"github.com/carabiner-dev/beaker/models"
+)
+
+const (
+	resultPass = "pass"
+	resultFail = "fail"
+)
+
+// DefaultSeparator joins the class name and test name in test identifiers
+const DefaultSeparator = "#"
+
+type Options struct {
+	// WorkDir is the directory where report paths are resolved
+	WorkDir string
+
+	// Reports are glob patterns of the XML report files to read. Patterns
+	// are relative to WorkDir and support ** to match any number of
+	// directories. When empty, the runner output is parsed as XML.
+	Reports []string
+
+	// Separator joins the class name and test name in test identifiers
+	Separator string
+
+	// Since, when set, skips report files last modified before it. Runners
+	// use it to ignore stale reports left by previous builds.
+	Since time.Time
+
+	// OptionalReports returns an empty result instead of an error when no
+	// report matches. Runners set it when the build failed before running
+	// any test.
+	OptionalReports bool
+}
+
+type OptFn func(*Options) error
+
+func WithWorkDir(dir string) OptFn {
+	return func(o *Options) error {
+		o.WorkDir = dir
+		return nil
+	}
+}
+
+// WithReports sets the glob patterns of the report files to read
+func WithReports(patterns ...string) OptFn {
+	return func(o *Options) error {
+		for _, p := range patterns {
+			if _, err := path.Match(p, ""); err != nil {
+				return fmt.Errorf("invalid report pattern %q: %w", p, err)
+			}
+		}
+		o.Reports = append(o.Reports, patterns...)
+		return nil
+	}
+}
+
+// WithSeparator sets the string that joins class and test names
+func WithSeparator(sep string) OptFn {
+	return func(o *Options) error {
+		o.Separator = sep
+		return nil
+	}
+}
+
+// WithSince ignores report files modified before t
+func WithSince(t time.Time) OptFn {
+	return func(o *Options) error {
+		o.Since = t
+		return nil
+	}
+}
+
+// WithOptionalReports sets if finding no report files is an empty result
+// instead of an error.
+func WithOptionalReports(optional bool) OptFn {
+	return func(o *Options) error {
+		o.OptionalReports = optional
+		return nil
+	}
+}
+
+// New returns a new JUnit XML parser
+func New(funcs ...OptFn) (*Parser, error) {
+	opts := Options{
+		WorkDir:   ".",
+		Reports:   []string{},
+		Separator: DefaultSeparator,
+	}
+	for _, f := range funcs {
+		if err := f(&opts); err != nil {
+			return nil, err
+		}
+	}
+	return &Parser{Options: opts}, nil
+}
+
+// Parser implements a ResultsParser that reads JUnit XML reports, the
+// format written by surefire, gradle, pytest, jest-junit, ctest and others.
+type Parser struct {
+	Options Options
+}
+
+// testSuite captures both <testsuites> and <testsuite> elements as some
+// tools nest suites within suites.
+type testSuite struct {
+	XMLName   xml.Name
+	Suites    []testSuite `xml:"testsuite"`
+	TestCases []testCase  `xml:"testcase"`
+}
+
+type testCase struct {
+	ClassName string    `xml:"classname,attr"`
+	Name      string    `xml:"name,attr"`
+	Failure   *struct{} `xml:"failure"`
+	Error     *struct{} `xml:"error"`
+	Skipped   *struct{} `xml:"skipped"`
+}
+
+// ParseResults reads the test results from the configured report files or,
+// if there are none, from the runner output. Test cases with a <failure> or
+// <error> are recorded as failed, skipped tests are not recorded as passing
+// but listed in the test details.
+func (p *Parser) ParseResults(_ context.Context, att *testresult.TestResult, res []byte) (*testresult.TestResult, error) {
+	if att == nil {
+		att = &testresult.TestResult{
+			Result:        resultPass,
+			Configuration: []*intoto.ResourceDescriptor{},
+			PassedTests:   []string{},
+			FailedTests:   []string{},
+		}
+	} else {
+		att.Result = resultPass
+		att.PassedTests = []string{}
+		att.FailedTests = []string{}
+	}
+
+	cases := []testCase{}
+	if len(p.Options.Reports) == 0 {
+		c, err := parseReport(res)
+		if err != nil {
+			return nil, err
+		}
+		cases = c
+	} else {
+		files, err := p.ReportFiles()
+		if err != nil {
+			return nil, err
+		}
+		if len(files) == 0 && !p.Options.OptionalReports {
+			return nil, fmt.Errorf("no junit reports found matching %s", strings.Join(p.Options.Reports, ", "))
+		}
+		for _, f := range files {
+			data, err := os.ReadFile(f)
+			if err != nil {
+				return nil, fmt.Errorf("reading report: %w", err)
+			}
+			c, err := parseReport(data)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", f, err)
+			}
+			cases = append(cases, c...)
+		}
+	}
+
+	details := &models.TestDetails{}
+	for i := range cases {
+		switch {
+		case cases[i].Failure != nil, cases[i].Error != nil:
+			att.FailedTests = append(att.FailedTests, p.testID(&cases[i]))
+		case cases[i].Skipped != nil:
+			details.Skipped = append(details.Skipped, p.testID(&cases[i]))
+		default:
+			att.PassedTests = append(att.PassedTests, p.testID(&cases[i]))
+		}
+	}
+
+	if len(att.GetFailedTests()) > 0 {
+		att.Result = resultFail
+	}
+
+	if err := models.SetTestDetails(att, details); err != nil {
+		return nil, fmt.Errorf("recording test details: %w", err)
+	}
+
+	return att, nil
+}
+
+// testID returns the identifier of a test case in the attestation
+func (p *Parser) testID(tc *testCase) string {
+	if tc.ClassName == "" {
+		return tc.Name
+	}
+	return tc.ClassName + p.Options.Separator + tc.Name
+}
+
+// ReportFiles returns the files matching the report patterns, sorted
+func (p *Parser) ReportFiles() ([]string, error) {
+	files := []string{}
+	err := filepath.WalkDir(p.Options.WorkDir, func(fpath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(p.Options.WorkDir, fpath)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		for _, pattern := range p.Options.Reports {
+			if !matchPath(strings.Split(pattern, "/"), strings.Split(rel, "/")) {
+				continue
+			}
+			if !p.Options.Since.IsZero() {
+				info, err := d.Info()
+				if err != nil {
+					return err
+				}
+				if info.ModTime().Before(p.Options.Since) {
+					break
+				}
+			}
+			files = append(files, fpath)
+			break
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("looking for report files: %w", err)
+	}
+	slices.Sort(files)
+	return files, nil
+}
+
+// matchPath matches path segments against pattern segments. A ** segment
+// matches zero or more path segments.
+func matchPath(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchPath(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if ok, err := path.Match(pattern[0], segments[0]); err != nil || !ok {
+		return false
+	}
+	return matchPath(pattern[1:], segments[1:])
+}
+
+// parseReport decodes all the test cases in XML data. The data may hold
+// more than one document root, for example when several reports are
+// concatenated in the runner output.
+func parseReport(data []byte) ([]testCase, error) {
+	cases := []testCase{}
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	found := false
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parsing junit xml: %w", err)
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch start.Name.Local {
+		case "testsuites", "testsuite":
+			suite := testSuite{}
+			if err := dec.DecodeElement(&suite, &start); err != nil {
+				return nil, fmt.Errorf("decoding test suite: %w", err)
+			}
+			cases = append(cases, collectCases(&suite)...)
+		case "testcase":
+			tc := testCase{}
+			if err := dec.DecodeElement(&tc, &start); err != nil {
+				return nil, fmt.Errorf("decoding test case: %w", err)
+			}
+			cases = append(cases, tc)
+		default:
+			return nil, fmt.Errorf("unexpected element <%s> in junit report", start.Name.Local)
+		}
+		found = true
+	}
+
+	if !found {
+		return nil, errors.New("no junit test suites found in report")
+	}
+	return cases, nil
+}
+
+// collectCases returns the test cases in a suite and its children
+func collectCases(suite *testSuite) []testCase {
+	cases := slices.Clone(suite.TestCases)
+	for i := range suite.Suites {
+		cases = append(cases, collectCases(&suite.Suites[i])...)
+	}
+	return cases
+}
@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+package junit
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/carabiner-dev/beaker/models"
+)
+
+func TestParseResults(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nested-suites", func(t *testing.T) {
+		t.Parallel()
+		data, err := os.ReadFile("testdata/nested.xml")
+		require.NoError(t, err)
+
+		p, err := New()
+		require.NoError(t, err)
+		att, err := p.ParseResults(t.Context(), nil, data)
+		require.NoError(t, err)
+		require.Equal(t, resultFail, att.GetResult())
+		require.Equal(t, []string{
+			"com.example.api.ServerTest#starts",
+			"com.example.api.ClientTest#connects",
+			"com.example.storage.DiskTest#writes",
+		}, att.GetPassedTests())
+		require.Equal(t, []string{
+			"com.example.api.ClientTest#retries",
+			"com.example.storage.DiskTest#reads",
+		}, att.GetFailedTests())
+
+		details, err := models.GetTestDetails(att)
+		require.NoError(t, err)
+		require.Equal(t, []string{"com.example.storage.DiskTest#encrypts"}, details.Skipped)
+	})
+
+	t.Run("report-files", func(t *testing.T) {
+		t.Parallel()
+		p, err := New(
+			WithWorkDir("testdata/reports"),
+			WithReports("**/target/surefire-reports/*.xml"),
+			WithSeparator("."),
+		)
+		require.NoError(t, err)
+
+		// The output is ignored when reading report files
+		att, err := p.ParseResults(t.Context(), nil, []byte("[INFO] BUILD FAILURE"))
+		require.NoError(t, err)
+		require.Equal(t, resultFail, att.GetResult())
+		require.Equal(t, []string{"com.example.a.AlphaTest.first", "com.example.a.AlphaTest.second"}, att.GetPassedTests())
+		require.Equal(t, []string{"com.example.b.BetaTest.breaks"}, att.GetFailedTests())
+	})
+
+	t.Run("no-reports", func(t *testing.T) {
+		t.Parallel()
+		p, err := New(WithWorkDir("testdata/reports"), WithReports("build/test-results/**/*.xml"))
+		require.NoError(t, err)
+		_, err = p.ParseResults(t.Context(), nil, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("concatenated", func(t *testing.T) {
+		t.Parallel()
+		data := strings.Join([]string{
+			`<testsuite name="a"><testcase classname="A" name="one"/></testsuite>`,
+			`<testsuite name="b"><testcase classname="B" name="two"><skipped/></testcase></testsuite>`,
+		}, "\n")
+		p, err := New()
+		require.NoError(t, err)
+		att, err := p.ParseResults(t.Context(), nil, []byte(data))
+		require.NoError(t, err)
+		require.Equal(t, resultPass, att.GetResult())
+		require.Equal(t, []string{"A#one"}, att.GetPassedTests())
+		require.Empty(t, att.GetFailedTests())
+
+		details, err := models.GetTestDetails(att)
+		require.NoError(t, err)
+		require.Equal(t, []string{"B#two"}, details.Skipped)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+		p, err := New()
+		require.NoError(t, err)
+		_, err = p.ParseResults(t.Context(), nil, []byte("ok 1 - not xml"))
+		require.Error(t, err)
+	})
+}
+
+func TestMatchPath(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		pattern string
+		path    string
+		match   bool
+	}{
+		{"*.xml", "TEST-a.xml", true},
+		{"*.xml", "sub/TEST-a.xml", false},
+		{"target/surefire-reports/*.xml", "target/surefire-reports/TEST-a.xml", true},
+		{"**/target/surefire-reports/*.xml", "target/surefire-reports/TEST-a.xml", true},
+		{"**/target/surefire-reports/*.xml", "a/b/target/surefire-reports/TEST-a.xml", true},
+		{"build/test-results/**/*.xml", "build/test-results/test/TEST-a.xml", true},
+		{"build/test-results/**/*.xml", "build/test-results/TEST-a.xml", true},
+		{"build/test-results/**/*.xml", "build/reports/TEST-a.xml", false},
+		{"**", "any/path/at/all", true},
+	} {
+		t.Run(tc.pattern+"|"+tc.path, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.match, matchPath(strings.Split(tc.pattern, "/"), strings.Split(tc.path, "/")))
+		})
+	}
+}
@@ -0,0 +1,138 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+package cargo
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	testresult "github.com/in-toto/attestation/go/predicates/test_result/v0"
+	intoto "github.com/in-toto/attestation/go/v1"
+
+	"github.com/carabiner-dev/beaker/models"
+)
+
+const (
+	resultPass = "pass"
+	resultFail = "fail"
+)
+
+var (
+	// runningLine matches the line cargo prints before running a test
+	// binary, e.g.:
+	//
+	//	Running unittests src/lib.rs (target/debug/deps/mycrate-1a2b3c4d5e6f7a8b)
+	//	Running tests/api.rs (target/debug/deps/api-1a2b3c4d5e6f7a8b)
+	runningLine = regexp.MustCompile(`^\s*Running\s+(?:unittests\s+)?\S+\s+\((.+)\)\s*$`)
+
+	// docTestsLine matches the header of the doc tests of a crate
+	docTestsLine = regexp.MustCompile(`^\s*Doc-tests\s+(\S+)\s*$`)
+
+	// humanLine matches a test outcome in the libtest human format:
+	//
+	//	test tests::it_works ... ok
+	//	test tests::it_breaks ... FAILED
+	//	test tests::slow ... ignored, takes too long
+	humanLine = regexp.MustCompile(`^test (.+?) \.\.\. (ok|FAILED|ignored)(?:,.*)?$`)
+
+	// binaryHash matches the hash cargo appends to test binary names
+	binaryHash = regexp.MustCompile(`-[0-9a-f]{16}$`)
+)
+
+// testEvent is a libtest JSON event
+type testEvent struct {
+	Type  string `json:"type"`
+	Event string `json:"event"`
+	Name  string `json:"name"`
+}
+
+// ParseResults parses the output of cargo test. It reads both the libtest
+// JSON events and the human readable format. Test names are prefixed with
+// the test target they belong to, so that tests from different crates in
+// a workspace don't collide. Ignored tests are recorded as skipped in the
+// test details.
+func (r *Runner) ParseResults(_ context.Context, att *testresult.TestResult, res []byte) (*testresult.TestResult, error) {
+	if att == nil {
+		att = &testresult.TestResult{
+			Result:        resultPass,
+			Configuration: []*intoto.ResourceDescriptor{},
+			PassedTests:   []string{},
+			FailedTests:   []string{},
+		}
+	} else {
+		att.Result = resultPass
+		att.PassedTests = []string{}
+		att.FailedTests = []string{}
+	}
+
+	details := &models.TestDetails{}
+	target := ""
+	scanner := bufio.NewScanner(bytes.NewReader(res))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if m := runningLine.FindStringSubmatch(line); m != nil {
+			target = targetName(m[1])
+			continue
+		}
+
+		if m := docTestsLine.FindStringSubmatch(line); m != nil {
+			target = m[1]
+			continue
+		}
+
+		name, outcome := "", ""
+		if strings.HasPrefix(strings.TrimSpace(line), "{") {
+			event := testEvent{}
+			if err := json.Unmarshal([]byte(line), &event); err != nil || event.Type != "test" {
+				continue
+			}
+			name, outcome = event.Name, event.Event
+		} else if m := humanLine.FindStringSubmatch(line); m != nil {
+			name, outcome = m[1], strings.ToLower(m[2])
+		}
+
+		if name == "" {
+			continue
+		}
+
+		if target != "" {
+			name = target + "::" + name
+		}
+
+		switch outcome {
+		case "ok":
+			att.PassedTests = append(att.PassedTests, name)
+		case "failed":
+			att.FailedTests = append(att.FailedTests, name)
+		case "ignored":
+			details.Skipped = append(details.Skipped, name)
+		}
+	}
+
+	if len(att.GetFailedTests()) > 0 {
+		att.Result = resultFail
+	}
+
+	if err := models.SetTestDetails(att, details); err != nil {
+		return nil, fmt.Errorf("recording test details: %w", err)
+	}
+
+	return att, nil
+}
+
+// targetName returns the name of the test target from the path to its
+// binary, dropping the hash suffix added by cargo.
+func targetName(binary string) string {
+	name := path.Base(strings.ReplaceAll(binary, `\`, "/"))
+	name = strings.TrimSuffix(name, ".exe")
+	return binaryHash.ReplaceAllString(name, "")
+}
@@ -0,0 +1,76 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+package cargo
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/carabiner-dev/beaker/models"
+)
+
+func TestParseResults(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name    string
+		file    string
+		result  string
+		passed  []string
+		failed  []string
+		skipped []string
+	}{
+		{
+			name:   "json-workspace",
+			file:   "testdata/workspace-json.txt",
+			result: resultFail,
+			passed: []string{
+				"core::tests::adds",
+				"cli::tests::adds",
+				"integration::end_to_end",
+				"core::src/lib.rs - add (line 5)",
+			},
+			failed:  []string{"core::tests::divides"},
+			skipped: []string{"core::tests::slow"},
+		},
+		{
+			name:    "human",
+			file:    "testdata/workspace-human.txt",
+			result:  resultFail,
+			passed:  []string{"core::tests::adds", "cli::tests::adds"},
+			failed:  []string{"core::tests::divides"},
+			skipped: []string{"core::tests::slow"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			data, err := os.ReadFile(tc.file)
+			require.NoError(t, err)
+
+			r := &Runner{}
+			att, err := r.ParseResults(t.Context(), nil, data)
+			require.NoError(t, err)
+			require.Equal(t, tc.result, att.GetResult())
+			require.Equal(t, tc.passed, att.GetPassedTests())
+			require.Equal(t, tc.failed, att.GetFailedTests())
+
+			details, err := models.GetTestDetails(att)
+			require.NoError(t, err)
+			require.Equal(t, tc.skipped, details.Skipped)
+		})
+	}
+}
+
+func TestTargetName(t *testing.T) {
+	t.Parallel()
+	for in, expected := range map[string]string{
+		"target/debug/deps/core-3f2a1b0c9d8e7f6a":      "core",
+		`target\debug\deps\cli-0a1b2c3d4e5f6789.exe`:   "cli",
+		"target/debug/deps/my-crate-0a1b2c3d4e5f6789":  "my-crate",
+		"target/x86_64-unknown-linux-gnu/debug/deps/a": "a",
+	} {
+		require.Equal(t, expected, targetName(in))
+	}
+}
@@ -0,0 +1,133 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+package cargo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+
+	"sigs.k8s.io/release-utils/helpers"
+
+	"github.com/carabiner-dev/beaker/pkg/runners/shell"
+)
+
+// nightlyVersion matches the versions of toolchains accepting unstable
+// options: nightly and locally built compilers.
+var nightlyVersion = regexp.MustCompile(`^rustc \S+-(nightly|dev)\b`)
+
+type Options struct {
+	WorkDir string
+
+	// JSON runs libtest with its JSON event format, which is only emitted
+	// behind -Z unstable-options. When not set, it is used if the
+	// toolchain accepts unstable options (nightly and dev builds) and the
+	// human readable output is parsed otherwise.
+	JSON *bool
+
+	// ShellOptions are applied to the shell runner after the defaults
+	ShellOptions []shell.OptFn
+}
+
+func WithWorkDir(path string) OptFn {
+	return func(o *Options) error {
+		if !helpers.IsDir(path) {
+			return fmt.Errorf("working dir does not exist: %q", path)
+		}
+		o.WorkDir = path
+		return nil
+	}
+}
+
+// WithJSON forces or disables the libtest JSON output format
+func WithJSON(useJSON bool) OptFn {
+	return func(o *Options) error {
+		o.JSON = &useJSON
+		return nil
+	}
+}
+
+// WithShellOptions overrides the command, arguments or environment
+// of the underlying shell runner.
+func WithShellOptions(funcs ...shell.OptFn) OptFn {
+	return func(o *Options) error {
+		o.ShellOptions = append(o.ShellOptions, funcs...)
+		return nil
+	}
+}
+
+type OptFn func(*Options) error
+
+// New returns a new cargo runner
+func New(funcs ...OptFn) (*Runner, error) {
+	opts := Options{
+		WorkDir: ".",
+	}
+
+	for _, f := range funcs {
+		if err := f(&opts); err != nil {
+			return nil, err
+		}
+	}
+
+	// Run all the crates in the workspace and don't stop at the first
+	// failing test binary.
+	args := []string{"test", "--workspace", "--no-fail-fast"}
+	defaults := []shell.OptFn{shell.WithWorkDir(opts.WorkDir), shell.WithCommand("cargo")}
+	useJSON := false
+	if opts.JSON != nil {
+		useJSON = *opts.JSON
+	} else {
+		useJSON = acceptsUnstable(toolchainVersion(opts.WorkDir))
+	}
+	if useJSON {
+		args = append(args, "--", "-Z", "unstable-options", "--format", "json")
+	}
+
+	// Cargo reports the test binary being run on stderr, we capture it
+	// to attribute the tests to their crates.
+	defaults = append(defaults, shell.WithArguments(args), shell.WithCaptureStderr(true))
+	shellrunner, err := shell.New(append(defaults, opts.ShellOptions...)...)
+	if err != nil {
+		return nil, err
+	}
+	return &Runner{
+		Options: opts,
+		runner:  shellrunner,
+	}, nil
+}
+
+// Runner implements a TestRunner that executes `cargo test`
+type Runner struct {
+	Options Options
+	runner  *shell.Runner
+}
+
+// Run runs the tests
+func (r *Runner) Run(ctx context.Context) (attestation []byte, pass bool, err error) {
+	return r.runner.Run(ctx)
+}
+
+// toolchainVersion returns the version of the rust compiler used in dir,
+// which honors rustup toolchain overrides. It returns an empty string if
+// rustc cannot be run.
+func toolchainVersion(dir string) string {
+	cmd := exec.Command("rustc", "--version")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return string(bytes.TrimSpace(out))
+}
+
+// acceptsUnstable reports if a rustc version string is from a toolchain
+// that accepts unstable options, for example:
+//
+//	rustc 1.92.0-nightly (a1b2c3d4e 2025-10-01)
+func acceptsUnstable(version string) bool {
+	return nightlyVersion.MatchString(version)
+}
@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+package cargo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/carabiner-dev/beaker/pkg/runners/shell"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name string
+		opts []OptFn
+		args []string
+		env  map[string]string
+	}{
+		{
+			name: "human",
+			opts: []OptFn{WithJSON(false)},
+			args: []string{"test", "--workspace", "--no-fail-fast"},
+			env:  map[string]string{},
+		},
+		{
+			name: "json",
+			opts: []OptFn{WithJSON(true)},
+			args: []string{"test", "--workspace", "--no-fail-fast", "--", "-Z", "unstable-options", "--format", "json"},
+			env:  map[string]string{},
+		},
+		{
+			name: "json-env",
+			opts: []OptFn{WithJSON(true), WithShellOptions(shell.WithEnvVar("CARGO_TERM_COLOR", "never"))},
+			args: []string{"test", "--workspace", "--no-fail-fast", "--", "-Z", "unstable-options", "--format", "json"},
+			env:  map[string]string{"CARGO_TERM_COLOR": "never"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			r, err := New(tc.opts...)
+			require.NoError(t, err)
+			require.Equal(t, "cargo", r.runner.Options.Command)
+			require.Equal(t, tc.args, r.runner.Options.Args)
+			require.Equal(t, tc.env, r.runner.Options.Env)
+			require.True(t, r.runner.Options.CaptureStderr)
+		})
+	}
+}
+
+func TestAcceptsUnstable(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		version  string
+		expected bool
+	}{
+		{"rustc 1.92.0-nightly (a1b2c3d4e 2025-10-01)", true},
+		{"rustc 1.93.0-dev", true},
+		{"rustc 1.90.0 (1159e78c4 2025-09-14)", false},
+		{"rustc 1.91.0-beta.3 (5c1f3a2b0 2025-10-10)", false},
+		{"", false},
+	} {
+		t.Run(tc.version, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.expected, acceptsUnstable(tc.version))
+		})
+	}
+}
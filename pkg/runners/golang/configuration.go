@@ -0,0 +1,60 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+package golang
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	intoto "github.com/in-toto/attestation/go/v1"
+	"sigs.k8s.io/release-utils/helpers"
+
+	"github.com/carabiner-dev/beaker/models"
+)
+
+var _ models.ConfigurationProvider = (*Runner)(nil)
+
+// Configuration returns the descriptors of the go toolchain that runs the
+// tests, annotated with its version and target platform, and of the
+// go.sum file of the module.
+func (r *Runner) Configuration(ctx context.Context) ([]*intoto.ResourceDescriptor, error) {
+	tool := r.runner.Options.Command
+
+	out, err := r.runner.Output(ctx, tool, "version")
+	if err != nil {
+		return nil, err
+	}
+	// go version go1.25.3 linux/amd64
+	fields := strings.Fields(string(out))
+	if len(fields) < 3 {
+		return nil, fmt.Errorf("unexpected go version output: %q", strings.TrimSpace(string(out)))
+	}
+
+	out, err = r.runner.Output(ctx, tool, "env", "GOOS", "GOARCH")
+	if err != nil {
+		return nil, err
+	}
+	goos, goarch, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+
+	toolchain, err := models.ToolDescriptor(tool, map[string]string{
+		"version": fields[2],
+		"goos":    strings.TrimSpace(goos),
+		"goarch":  strings.TrimSpace(goarch),
+	})
+	if err != nil {
+		return nil, err
+	}
+	rds := []*intoto.ResourceDescriptor{toolchain}
+
+	if path := filepath.Join(r.Options.WorkDir, "go.sum"); helpers.Exists(path) {
+		rd, err := models.FileDescriptor(path, "go.sum")
+		if err != nil {
+			return nil, err
+		}
+		rds = append(rds, rd)
+	}
+	return rds, nil
+}
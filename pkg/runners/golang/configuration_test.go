@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+package golang
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfiguration(t *testing.T) {
+	t.Parallel()
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go is not in the PATH")
+	}
+
+	dir := t.TempDir()
+	gosum := []byte("example.com/m v1.0.0 h1:AAAA=\n")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.sum"), gosum, 0o600))
+
+	r, err := New(WithWorkDir(dir))
+	require.NoError(t, err)
+	rds, err := r.Configuration(t.Context())
+	require.NoError(t, err)
+	require.Len(t, rds, 2)
+
+	require.Equal(t, "go", rds[0].GetName())
+	require.Len(t, rds[0].GetDigest()["sha256"], 64)
+	annotations := rds[0].GetAnnotations().AsMap()
+	require.True(t, strings.HasPrefix(annotations["version"].(string), "go"))
+	require.NotEmpty(t, annotations["goos"])
+	require.NotEmpty(t, annotations["goarch"])
+
+	sum := sha256.Sum256(gosum)
+	require.Equal(t, "go.sum", rds[1].GetName())
+	require.Equal(t, hex.EncodeToString(sum[:]), rds[1].GetDigest()["sha256"])
+}
@@ -4,15 +4,21 @@
 package golang
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
-	"log"
+	"strings"
 	"time"
 
 	testresult "github.com/in-toto/attestation/go/predicates/test_result/v0"
 	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/sirupsen/logrus"
+
+	"github.com/carabiner-dev/beaker/models"
 )
 
 const (
@@ -20,18 +26,64 @@ const (
 	resultFail = "fail"
 )
 
+const (
+	// BuildFailedMarker is appended to the import path of packages that
+	// failed to build when recording them as failed entries.
+	BuildFailedMarker = "[build failed]"
+
+	// PackageFailedMarker is appended to the import path of packages that
+	// failed outside of any test (a panic in TestMain or init, a crash).
+	PackageFailedMarker = "[package failed]"
+)
+
 type testLine struct {
-	Time    time.Time `json:"Time"`
-	Action  string    `json:"Action"`
-	Package string    `json:"Package"`
-	Output  string    `json:"Output"`
-	Test    string    `json:"Test"`
-	Elapsed float32   `json:"Elapsed"`
+	Time        time.Time `json:"Time"`
+	Action      string    `json:"Action"`
+	Package     string    `json:"Package"`
+	ImportPath  string    `json:"ImportPath"`
+	Output      string    `json:"Output"`
+	Test        string    `json:"Test"`
+	Elapsed     float64   `json:"Elapsed"`
+	FailedBuild string    `json:"FailedBuild"`
+}
+
+// packageState tracks the events of a package while parsing
+type packageState struct {
+	failedTests int
+	buildFailed bool
+	output      strings.Builder
+	running     []string
+	testOutput  map[string]*strings.Builder
 }
 
-// ParseResults parses the structures output of the go tests
+// finish marks a test as done, discarding its output
+func (ps *packageState) finish(test string) {
+	for i, name := range ps.running {
+		if name == test {
+			ps.running = append(ps.running[:i], ps.running[i+1:]...)
+			break
+		}
+	}
+	delete(ps.testOutput, test)
+}
+
+// resultsParser accumulates the results read from the go test output
+type resultsParser struct {
+	outputLimit int
+	att         *testresult.TestResult
+	details     *models.TestDetails
+	packages    map[string]*packageState
+	buildOutput map[string]*strings.Builder
+}
+
+// ParseResults parses the structures output of the go tests. Besides the
+// test outcomes, packages that fail to build or fail outside of a test
+// are recorded as failed entries with the package path and a marker. The
+// output of each failed entry is recorded in the test details, truncated
+// to the configured limit.
+// Skipped tests and the elapsed time of each test are recorded in the test
+// details.
 func (r *Runner) ParseResults(ctx context.Context, att *testresult.TestResult, res []byte) (*testresult.TestResult, error) {
-	dec := json.NewDecoder(bytes.NewReader(res))
 	if att == nil {
 		att = &testresult.TestResult{
 			Result:        resultPass, // will change below if tests fail
@@ -45,33 +97,171 @@ func (r *Runner) ParseResults(ctx context.Context, att *testresult.TestResult, r
 		att.FailedTests = []string{}
 	}
 
-	for {
-		var result testLine
+	p := &resultsParser{
+		outputLimit: r.Options.OutputLimit,
+		att:         att,
+		details:     &models.TestDetails{},
+		packages:    map[string]*packageState{},
+		buildOutput: map[string]*strings.Builder{},
+	}
 
-		err := dec.Decode(&result)
-		if err == io.EOF {
+	// The output is read line by line as it may be interleaved with
+	// non-JSON lines from the go command (downloads, build errors).
+	reader := bufio.NewReader(bytes.NewReader(res))
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var event testLine
+			if jerr := json.Unmarshal(line, &event); jerr != nil {
+				logrus.Debugf("skipping non-JSON line in go test output: %q", strings.TrimSpace(string(line)))
+			} else {
+				p.handle(&event)
+			}
+		}
+		if errors.Is(err, io.EOF) {
 			break
 		}
 		if err != nil {
-			log.Printf("Parsing line: %v", err)
-			continue
+			return nil, fmt.Errorf("reading test output: %w", err)
 		}
+	}
+
+	if len(att.GetFailedTests()) > 0 {
+		att.Result = resultFail
+	}
+
+	if err := models.SetTestDetails(att, p.details); err != nil {
+		return nil, fmt.Errorf("recording test details: %w", err)
+	}
 
-		if result.Test == "" {
-			continue
+	return att, nil
+}
+
+// pkg returns the state of a package, creating it if needed
+func (p *resultsParser) pkg(name string) *packageState {
+	if _, ok := p.packages[name]; !ok {
+		p.packages[name] = &packageState{testOutput: map[string]*strings.Builder{}}
+	}
+	return p.packages[name]
+}
+
+// handle processes a single event of the go test output
+func (p *resultsParser) handle(event *testLine) {
+	switch event.Action {
+	case "build-output":
+		path := importPath(event.ImportPath)
+		if _, ok := p.buildOutput[path]; !ok {
+			p.buildOutput[path] = &strings.Builder{}
 		}
+		p.buildOutput[path].WriteString(event.Output)
+		return
+	case "build-fail":
+		p.buildFailed(importPath(event.ImportPath), "")
+		return
+	}
 
-		switch result.Action {
+	ps := p.pkg(event.Package)
+	if event.Test == "" {
+		switch event.Action {
+		case "output":
+			ps.output.WriteString(event.Output)
 		case "fail":
-			att.FailedTests = append(att.FailedTests, result.Test)
-		case "pass":
-			att.PassedTests = append(att.PassedTests, result.Test)
+			p.packageFailed(event, ps)
 		}
+		return
 	}
 
-	if len(att.GetFailedTests()) > 0 {
-		att.Result = resultFail
+	switch event.Action {
+	case "run":
+		ps.running = append(ps.running, event.Test)
+	case "output":
+		if _, ok := ps.testOutput[event.Test]; !ok {
+			ps.testOutput[event.Test] = &strings.Builder{}
+		}
+		ps.testOutput[event.Test].WriteString(event.Output)
+	case "fail":
+		p.att.FailedTests = append(p.att.FailedTests, event.Test)
+		p.details.AddDuration(event.Test, event.Elapsed)
+		if out, ok := ps.testOutput[event.Test]; ok {
+			p.recordOutput(event.Test, out.String())
+		}
+		ps.failedTests++
+		ps.finish(event.Test)
+	case "pass":
+		p.att.PassedTests = append(p.att.PassedTests, event.Test)
+		p.details.AddDuration(event.Test, event.Elapsed)
+		ps.finish(event.Test)
+	case "skip":
+		p.details.Skipped = append(p.details.Skipped, event.Test)
+		ps.finish(event.Test)
 	}
+}
 
-	return att, nil
+// packageFailed handles a fail action of a package. Tests still running
+// when the package fails (eg when the test binary times out or crashes)
+// are recorded as failed. A package failing without any failed tests is
+// recorded as a failed entry with its output.
+func (p *resultsParser) packageFailed(event *testLine, ps *packageState) {
+	// Older go versions do not emit build events, the failure is only
+	// noted in the package output.
+	if event.FailedBuild != "" || strings.Contains(ps.output.String(), BuildFailedMarker) {
+		path := event.Package
+		if event.FailedBuild != "" {
+			path = importPath(event.FailedBuild)
+		}
+		p.buildFailed(path, ps.output.String())
+		return
+	}
+
+	for _, test := range ps.running {
+		p.att.FailedTests = append(p.att.FailedTests, test)
+		ps.failedTests++
+		if out, ok := ps.testOutput[test]; ok {
+			p.recordOutput(test, out.String())
+		}
+	}
+	ps.running = nil
+
+	if ps.failedTests > 0 {
+		return
+	}
+
+	entry := fmt.Sprintf("%s %s", event.Package, PackageFailedMarker)
+	p.att.FailedTests = append(p.att.FailedTests, entry)
+	p.recordOutput(entry, ps.output.String())
+}
+
+// buildFailed records a package that did not build. The build output is
+// preferred when available, falling back to the package output.
+func (p *resultsParser) buildFailed(path, output string) {
+	ps := p.pkg(path)
+	if ps.buildFailed {
+		return
+	}
+	ps.buildFailed = true
+
+	entry := fmt.Sprintf("%s %s", path, BuildFailedMarker)
+	p.att.FailedTests = append(p.att.FailedTests, entry)
+	if b, ok := p.buildOutput[path]; ok {
+		output = b.String()
+	}
+	p.recordOutput(entry, output)
+}
+
+// recordOutput stores the output of a failed entry in the details
+func (p *resultsParser) recordOutput(entry, output string) {
+	if output == "" {
+		return
+	}
+	if p.details.Output == nil {
+		p.details.Output = map[string]string{}
+	}
+	p.details.Output[entry] = models.TruncateOutput(output, p.outputLimit)
+}
+
+// importPath returns the package path of an import path as reported in
+// build events, which may include the test variant ("p [p.test]").
+func importPath(path string) string {
+	path, _, _ = strings.Cut(path, " ")
+	return path
 }
@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+package golang
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/carabiner-dev/beaker/models"
+)
+
+func TestParseResults(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name      string
+		fixture   string
+		result    string
+		passed    []string
+		failed    []string
+		output    map[string]string
+		skipped   []string
+		durations map[string]float64
+	}{
+		{
+			name:      "mixed",
+			fixture:   "testdata/mixed.json",
+			result:    resultFail,
+			passed:    []string{"TestOK"},
+			failed:    []string{"TestBad"},
+			output:    map[string]string{"TestBad": "m_test.go:7: boom"},
+			durations: map[string]float64{"TestOK": 0, "TestBad": 1.5},
+		},
+		{
+			name:    "build-failure",
+			fixture: "testdata/build-failure.json",
+			result:  resultFail,
+			passed:  []string{},
+			failed:  []string{"example.com/build [build failed]"},
+			output: map[string]string{
+				"example.com/build [build failed]": "cannot use \"nope\" (untyped string constant) as int value",
+			},
+		},
+		{
+			name:    "testmain-panic",
+			fixture: "testdata/testmain-panic.json",
+			result:  resultFail,
+			passed:  []string{},
+			failed:  []string{"example.com/panic [package failed]"},
+			output: map[string]string{
+				"example.com/panic [package failed]": "panic: setup failed",
+			},
+		},
+		{
+			name:    "timeout",
+			fixture: "testdata/timeout.json",
+			result:  resultFail,
+			passed:  []string{"TestFast"},
+			failed:  []string{"TestSlow"},
+			output: map[string]string{
+				"TestSlow": "panic: test timed out after 1s",
+			},
+		},
+		{
+			name:    "skip",
+			fixture: "testdata/skip.json",
+			result:  resultPass,
+			passed:  []string{"TestRuns"},
+			failed:  []string{},
+			skipped: []string{"TestSkipped"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			data, err := os.ReadFile(tc.fixture)
+			require.NoError(t, err)
+
+			r, err := New()
+			require.NoError(t, err)
+			att, err := r.ParseResults(t.Context(), nil, data)
+			require.NoError(t, err)
+			require.Equal(t, tc.result, att.GetResult())
+			require.Equal(t, tc.passed, att.GetPassedTests())
+			require.Equal(t, tc.failed, att.GetFailedTests())
+
+			details, err := models.GetTestDetails(att)
+			require.NoError(t, err)
+			require.Len(t, details.Output, len(tc.output))
+			for entry, fragment := range tc.output {
+				require.Contains(t, details.Output[entry], fragment)
+			}
+			require.Equal(t, tc.skipped, details.Skipped)
+			if tc.durations != nil {
+				require.Equal(t, tc.durations, details.Durations)
+			}
+		})
+	}
+}
+
+func TestParseResultsOutputLimit(t *testing.T) {
+	t.Parallel()
+	data, err := os.ReadFile("testdata/mixed.json")
+	require.NoError(t, err)
+
+	r, err := New(WithOutputLimit(26))
+	require.NoError(t, err)
+	att, err := r.ParseResults(t.Context(), nil, data)
+	require.NoError(t, err)
+
+	details, err := models.GetTestDetails(att)
+	require.NoError(t, err)
+	require.Equal(t, "[... 40 bytes truncated]\n--- FAIL: TestBad (1.50s)\n", details.Output["TestBad"])
+}
+
+func TestParseResultsLegacyBuildFailure(t *testing.T) {
+	t.Parallel()
+	// Before go 1.24 build errors went to stderr and the package failure
+	// was only noted in the package output.
+	data := []byte(`{"Action":"start","Package":"example.com/old"}
+{"Action":"output","Package":"example.com/old","Output":"FAIL\texample.com/old [build failed]\n"}
+{"Action":"fail","Package":"example.com/old","Elapsed":0}
+`)
+	r, err := New()
+	require.NoError(t, err)
+	att, err := r.ParseResults(t.Context(), nil, data)
+	require.NoError(t, err)
+	require.Equal(t, resultFail, att.GetResult())
+	require.Equal(t, []string{"example.com/old [build failed]"}, att.GetFailedTests())
+}
@@ -9,11 +9,19 @@ import (
 
 	"sigs.k8s.io/release-utils/helpers"
 
+	"github.com/carabiner-dev/beaker/models"
 	"github.com/carabiner-dev/beaker/pkg/runners/shell"
 )
 
 type Options struct {
 	WorkDir string
+
+	// ShellOptions are applied to the shell runner after the defaults
+	ShellOptions []shell.OptFn
+
+	// OutputLimit is the maximum size of the output recorded for each
+	// failed test. Zero records the full output.
+	OutputLimit int
 }
 
 func WithWorkDir(path string) OptFn {
@@ -26,12 +34,34 @@ func WithWorkDir(path string) OptFn {
 	}
 }
 
+// WithShellOptions overrides the command, arguments or environment
+// of the underlying shell runner.
+func WithShellOptions(funcs ...shell.OptFn) OptFn {
+	return func(o *Options) error {
+		o.ShellOptions = append(o.ShellOptions, funcs...)
+		return nil
+	}
+}
+
+// WithOutputLimit sets the maximum size of the output recorded for each
+// failed test
+func WithOutputLimit(limit int) OptFn {
+	return func(o *Options) error {
+		if limit < 0 {
+			return fmt.Errorf("invalid output limit %d", limit)
+		}
+		o.OutputLimit = limit
+		return nil
+	}
+}
+
 type OptFn func(*Options) error
 
 // New returns a new go runner
 func New(funcs ...OptFn) (*Runner, error) {
 	opts := Options{
-		WorkDir: ".",
+		WorkDir:     ".",
+		OutputLimit: models.DefaultOutputLimit,
 	}
 
 	for _, f := range funcs {
@@ -39,11 +69,11 @@ func New(funcs ...OptFn) (*Runner, error) {
 			return nil, err
 		}
 	}
-	shellrunner, err := shell.New(
+	shellrunner, err := shell.New(append([]shell.OptFn{
 		shell.WithWorkDir(opts.WorkDir),
 		shell.WithCommand("go"),
 		shell.WithArguments([]string{"test", "-json", "./..."}),
-	)
+	}, opts.ShellOptions...)...)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+package gradle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/carabiner-dev/beaker/pkg/runners/shell"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	t.Run("gradle", func(t *testing.T) {
+		t.Parallel()
+		r, err := New(WithWorkDir(t.TempDir()))
+		require.NoError(t, err)
+		require.Equal(t, "gradle", r.runner.Options.Command)
+	})
+
+	t.Run("wrapper", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "gradlew"), []byte("#!/bin/sh\n"), 0o755)) //nolint:gosec
+		r, err := New(WithWorkDir(dir))
+		require.NoError(t, err)
+		require.Equal(t, "./gradlew", r.runner.Options.Command)
+	})
+}
+
+func TestParseResults(t *testing.T) {
+	t.Parallel()
+	r, err := New(WithWorkDir("testdata/multiproject"))
+	require.NoError(t, err)
+
+	att, err := r.ParseResults(t.Context(), nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, "fail", att.GetResult())
+	require.Equal(t, []string{
+		"com.example.app.MainTest#startsUp()",
+		"com.example.lib.StoreIT#persists()",
+		"com.example.lib.MathTest#adds(int, int)[1]",
+		"com.example.lib.MathTest#adds(int, int)[2]",
+	}, att.GetPassedTests())
+	require.Equal(t, []string{"com.example.app.MainTest#printsVersion()"}, att.GetFailedTests())
+}
+
+func TestParseResultsNoReports(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name    string
+		args    []string
+		pass    bool
+		mustErr bool
+	}{
+		// A build that fails before testing (like a compile error) writes
+		// no reports. The go command stands in for the build tool.
+		{"failed-build", []string{"nonexistent-command"}, false, false},
+		{"no-tests-run", []string{"version"}, true, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			r, err := New(
+				WithWorkDir(t.TempDir()),
+				WithShellOptions(shell.WithCommand("go"), shell.WithArguments(tc.args)),
+			)
+			require.NoError(t, err)
+			output, pass, err := r.Run(t.Context())
+			require.NoError(t, err)
+			require.Equal(t, tc.pass, pass)
+
+			att, err := r.ParseResults(t.Context(), nil, output)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "pass", att.GetResult())
+			require.Empty(t, att.GetPassedTests())
+			require.Empty(t, att.GetFailedTests())
+		})
+	}
+}
@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+package maven
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	testresult "github.com/in-toto/attestation/go/predicates/test_result/v0"
+	"sigs.k8s.io/release-utils/helpers"
+
+	"github.com/carabiner-dev/beaker/pkg/parsers/junit"
+	"github.com/carabiner-dev/beaker/pkg/runners/shell"
+)
+
+// ReportsPattern matches the surefire reports of the project and all
+// of its modules.
+const ReportsPattern = "**/target/surefire-reports/*.xml"
+
+type Options struct {
+	WorkDir string
+
+	// ShellOptions are applied to the shell runner after the defaults
+	ShellOptions []shell.OptFn
+}
+
+func WithWorkDir(path string) OptFn {
+	return func(o *Options) error {
+		if !helpers.IsDir(path) {
+			return fmt.Errorf("working dir does not exist: %q", path)
+		}
+		o.WorkDir = path
+		return nil
+	}
+}
+
+// WithShellOptions overrides the command, arguments or environment
+// of the underlying shell runner.
+func WithShellOptions(funcs ...shell.OptFn) OptFn {
+	return func(o *Options) error {
+		o.ShellOptions = append(o.ShellOptions, funcs...)
+		return nil
+	}
+}
+
+type OptFn func(*Options) error
+
+// New returns a new maven runner
+func New(funcs ...OptFn) (*Runner, error) {
+	opts := Options{
+		WorkDir: ".",
+	}
+
+	for _, f := range funcs {
+		if err := f(&opts); err != nil {
+			return nil, err
+		}
+	}
+
+	// Run in batch mode and keep testing the rest of the modules
+	// when one of them fails.
+	shellrunner, err := shell.New(append([]shell.OptFn{
+		shell.WithWorkDir(opts.WorkDir),
+		shell.WithCommand("mvn"),
+		shell.WithArguments([]string{"--batch-mode", "--fail-at-end", "test"}),
+	}, opts.ShellOptions...)...)
+	if err != nil {
+		return nil, err
+	}
+	return &Runner{
+		Options: opts,
+		runner:  shellrunner,
+	}, nil
+}
+
+// Runner implements a TestRunner that executes `mvn test` and reads the
+// surefire XML reports of all the modules in the build.
+type Runner struct {
+	Options Options
+	runner  *shell.Runner
+	started time.Time
+	failed  bool
+}
+
+// Run runs the tests
+func (r *Runner) Run(ctx context.Context) (attestation []byte, pass bool, err error) {
+	// Reports written before the run are stale. Truncate the start time
+	// to cope with filesystems that store coarse modification times.
+	r.started = time.Now().Truncate(time.Second)
+	attestation, pass, err = r.runner.Run(ctx)
+	r.failed = !pass
+	return attestation, pass, err
+}
+
+// ParseResults reads the surefire reports. The maven output is ignored.
+func (r *Runner) ParseResults(ctx context.Context, att *testresult.TestResult, res []byte) (*testresult.TestResult, error) {
+	parser, err := junit.New(
+		junit.WithWorkDir(r.Options.WorkDir),
+		junit.WithReports(ReportsPattern),
+		junit.WithSince(r.started),
+		// A build that fails to compile writes no reports, the launcher
+		// decides the outcome of the empty result.
+		junit.WithOptionalReports(r.failed),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating junit parser: %w", err)
+	}
+	return parser.ParseResults(ctx, att, res)
+}
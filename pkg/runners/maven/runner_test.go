@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+package maven
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/carabiner-dev/beaker/pkg/runners/shell"
+)
+
+func TestParseResults(t *testing.T) {
+	t.Parallel()
+
+	t.Run("multi-module", func(t *testing.T) {
+		t.Parallel()
+		r, err := New(WithWorkDir("testdata/multimodule"))
+		require.NoError(t, err)
+
+		att, err := r.ParseResults(t.Context(), nil, []byte("[INFO] BUILD FAILURE"))
+		require.NoError(t, err)
+		require.Equal(t, "fail", att.GetResult())
+		require.Equal(t, []string{
+			"com.example.core.ParserTest#parsesEmpty",
+			"com.example.core.ParserTest#parsesNested",
+			"com.example.web.RouterTest#routesIndex",
+		}, att.GetPassedTests())
+		require.Equal(t, []string{"com.example.web.RouterTest#routesMissing"}, att.GetFailedTests())
+	})
+
+	t.Run("stale-reports", func(t *testing.T) {
+		t.Parallel()
+		r, err := New(WithWorkDir("testdata/multimodule"))
+		require.NoError(t, err)
+
+		// Reports older than the run must not be read
+		r.started = time.Now().Add(time.Hour)
+		_, err = r.ParseResults(t.Context(), nil, nil)
+		require.Error(t, err)
+	})
+}
+
+func TestParseResultsNoReports(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name    string
+		args    []string
+		pass    bool
+		mustErr bool
+	}{
+		// A build that fails before testing (like a compile error) writes
+		// no reports. The go command stands in for the build tool.
+		{"failed-build", []string{"nonexistent-command"}, false, false},
+		{"no-tests-run", []string{"version"}, true, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			r, err := New(
+				WithWorkDir(t.TempDir()),
+				WithShellOptions(shell.WithCommand("go"), shell.WithArguments(tc.args)),
+			)
+			require.NoError(t, err)
+			output, pass, err := r.Run(t.Context())
+			require.NoError(t, err)
+			require.Equal(t, tc.pass, pass)
+
+			att, err := r.ParseResults(t.Context(), nil, output)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "pass", att.GetResult())
+			require.Empty(t, att.GetPassedTests())
+			require.Empty(t, att.GetFailedTests())
+		})
+	}
+}
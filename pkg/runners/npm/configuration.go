@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+package npm
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	intoto "github.com/in-toto/attestation/go/v1"
+	"sigs.k8s.io/release-utils/helpers"
+
+	"github.com/carabiner-dev/beaker/models"
+)
+
+// lockFiles are the npm lockfiles recorded when present in the project
+var lockFiles = []string{"package-lock.json", "npm-shrinkwrap.json"}
+
+var _ models.ConfigurationProvider = (*Runner)(nil)
+
+// Configuration returns the descriptors of the node and npm executables,
+// annotated with their versions, and of the project lockfile.
+func (r *Runner) Configuration(ctx context.Context) ([]*intoto.ResourceDescriptor, error) {
+	rds := []*intoto.ResourceDescriptor{}
+	for _, tool := range []string{"node", r.runner.Options.Command} {
+		out, err := r.runner.Output(ctx, tool, "--version")
+		if err != nil {
+			return nil, err
+		}
+		rd, err := models.ToolDescriptor(tool, map[string]string{
+			"version": strings.TrimSpace(string(out)),
+		})
+		if err != nil {
+			return nil, err
+		}
+		rds = append(rds, rd)
+	}
+
+	for _, name := range lockFiles {
+		path := filepath.Join(r.Options.WorkDir, name)
+		if !helpers.Exists(path) {
+			continue
+		}
+		rd, err := models.FileDescriptor(path, name)
+		if err != nil {
+			return nil, err
+		}
+		rds = append(rds, rd)
+	}
+	return rds, nil
+}
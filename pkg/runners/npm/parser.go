@@ -7,11 +7,15 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 
 	testresult "github.com/in-toto/attestation/go/predicates/test_result/v0"
 	intoto "github.com/in-toto/attestation/go/v1"
+
+	"github.com/carabiner-dev/beaker/models"
 )
 
 // tapLine matches a TAP test point line, e.g.:
@@ -22,8 +26,21 @@ import (
 // Leading whitespace is allowed so that subtests are picked up too.
 var tapLine = regexp.MustCompile(`^\s*(not )?ok\s+\d+(?:\s*-?\s*(.*))?$`)
 
+// tapDirective matches the SKIP and TODO directives after the description.
+// As in the TAP spec, they are case insensitive and SKIP may be followed by
+// other characters ("# skipped").
+var tapDirective = regexp.MustCompile(`(?i)^(skip|todo)`)
+
+// tapDuration matches the duration_ms key in the YAML diagnostics block
+// that follows a test point, as written by node:test and others.
+var tapDuration = regexp.MustCompile(`^\s*duration_ms:\s*([0-9.eE+-]+)\s*$`)
+
 // ParseResults extracts test names and pass/fail status from TAP output
-// emitted by the underlying npm test framework.
+// emitted by the underlying npm test framework. Skipped tests and tests
+// marked as TODO are recorded in the test details. Failing TODO tests are
+// only counted as failures when the runner is configured to do so. The
+// duration_ms found in the YAML diagnostics of a test is recorded as its
+// duration, and the diagnostics of failed tests as their output.
 func (r *Runner) ParseResults(_ context.Context, att *testresult.TestResult, res []byte) (*testresult.TestResult, error) {
 	if att == nil {
 		att = &testresult.TestResult{
@@ -38,28 +55,79 @@ func (r *Runner) ParseResults(_ context.Context, att *testresult.TestResult, res
 		att.FailedTests = []string{}
 	}
 
+	details := &models.TestDetails{}
+	// last is the test point the diagnostics block belongs to
+	last := ""
+	lastFailed := false
+	inYAML := false
+	indent := ""
+	var diag strings.Builder
 	scanner := bufio.NewScanner(bytes.NewReader(res))
 	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
 	for scanner.Scan() {
 		line := scanner.Text()
+		switch strings.TrimSpace(line) {
+		case "---":
+			inYAML = last != ""
+			indent = line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+			diag.Reset()
+			continue
+		case "...":
+			if inYAML && lastFailed && diag.Len() > 0 {
+				if details.Output == nil {
+					details.Output = map[string]string{}
+				}
+				details.Output[last] = models.TruncateOutput(diag.String(), r.Options.OutputLimit)
+			}
+			inYAML = false
+			continue
+		}
+		if inYAML {
+			if d := tapDuration.FindStringSubmatch(line); d != nil {
+				if ms, err := strconv.ParseFloat(d[1], 64); err == nil {
+					details.AddDuration(last, ms/1000)
+				}
+				continue
+			}
+			diag.WriteString(strings.TrimPrefix(line, indent) + "\n")
+			continue
+		}
+
 		m := tapLine.FindStringSubmatch(line)
 		if m == nil {
 			continue
 		}
 
 		name := strings.TrimSpace(m[2])
-		// Strip trailing TAP directives like "# SKIP" or "# TODO".
+		// Split trailing TAP directives like "# SKIP" or "# TODO".
+		directive := ""
 		if i := strings.Index(name, "#"); i >= 0 {
+			directive = strings.ToLower(tapDirective.FindString(strings.TrimSpace(name[i+1:])))
 			name = strings.TrimSpace(name[:i])
 		}
+		last = name
+		lastFailed = false
 		if name == "" {
 			continue
 		}
 
-		if m[1] == "" {
+		passed := m[1] == ""
+		switch directive {
+		case "skip":
+			details.Skipped = append(details.Skipped, name)
+			continue
+		case "todo":
+			details.Todo = append(details.Todo, name)
+			if !passed && !r.Options.TodoFailures {
+				continue
+			}
+		}
+
+		if passed {
 			att.PassedTests = append(att.PassedTests, name)
 		} else {
 			att.FailedTests = append(att.FailedTests, name)
+			lastFailed = true
 		}
 	}
 
@@ -67,5 +135,9 @@ func (r *Runner) ParseResults(_ context.Context, att *testresult.TestResult, res
 		att.Result = "fail"
 	}
 
+	if err := models.SetTestDetails(att, details); err != nil {
+		return nil, fmt.Errorf("recording test details: %w", err)
+	}
+
 	return att, nil
 }
@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+package npm
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/carabiner-dev/beaker/models"
+)
+
+func TestParseResults(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name         string
+		todoFailures bool
+		failed       []string
+	}{
+		{"todo-not-failures", false, []string{"writer"}},
+		{"todo-failures", true, []string{"parses dates", "writer"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			data, err := os.ReadFile("testdata/directives.tap")
+			require.NoError(t, err)
+
+			r, err := New(WithTodoFailures(tc.todoFailures))
+			require.NoError(t, err)
+			att, err := r.ParseResults(t.Context(), nil, data)
+			require.NoError(t, err)
+			require.Equal(t, "fail", att.GetResult())
+			require.Equal(t, []string{"parses numbers", "parses booleans", "parser"}, att.GetPassedTests())
+			require.Equal(t, tc.failed, att.GetFailedTests())
+
+			details, err := models.GetTestDetails(att)
+			require.NoError(t, err)
+			require.Equal(t, []string{"parses strings", "network"}, details.Skipped)
+			require.Equal(t, []string{"parses dates", "parses booleans"}, details.Todo)
+			require.Equal(t, map[string]float64{"parses numbers": 0.0125, "writer": 0.25}, details.Durations)
+			require.Equal(t, map[string]string{"writer": "message: 'expected 1 to equal 2'\n"}, details.Output)
+		})
+	}
+}
@@ -9,11 +9,23 @@ import (
 
 	"sigs.k8s.io/release-utils/helpers"
 
+	"github.com/carabiner-dev/beaker/models"
 	"github.com/carabiner-dev/beaker/pkg/runners/shell"
 )
 
 type Options struct {
 	WorkDir string
+
+	// ShellOptions are applied to the shell runner after the defaults
+	ShellOptions []shell.OptFn
+
+	// TodoFailures counts the failures of tests marked as TODO as failed
+	// tests. By default they are only recorded as TODO, as in the TAP spec.
+	TodoFailures bool
+
+	// OutputLimit is the maximum size of the diagnostics recorded for each
+	// failed test. Zero records the full diagnostics.
+	OutputLimit int
 }
 
 func WithWorkDir(path string) OptFn {
@@ -26,12 +38,42 @@ func WithWorkDir(path string) OptFn {
 	}
 }
 
+// WithShellOptions overrides the command, arguments or environment
+// of the underlying shell runner.
+func WithShellOptions(funcs ...shell.OptFn) OptFn {
+	return func(o *Options) error {
+		o.ShellOptions = append(o.ShellOptions, funcs...)
+		return nil
+	}
+}
+
+// WithTodoFailures sets if failing TODO tests count as failures
+func WithTodoFailures(todoFailures bool) OptFn {
+	return func(o *Options) error {
+		o.TodoFailures = todoFailures
+		return nil
+	}
+}
+
+// WithOutputLimit sets the maximum size of the diagnostics recorded for
+// each failed test
+func WithOutputLimit(limit int) OptFn {
+	return func(o *Options) error {
+		if limit < 0 {
+			return fmt.Errorf("invalid output limit %d", limit)
+		}
+		o.OutputLimit = limit
+		return nil
+	}
+}
+
 type OptFn func(*Options) error
 
 // New returns a new npm runner
 func New(funcs ...OptFn) (*Runner, error) {
 	opts := Options{
-		WorkDir: ".",
+		WorkDir:     ".",
+		OutputLimit: models.DefaultOutputLimit,
 	}
 
 	for _, f := range funcs {
@@ -39,11 +81,11 @@ func New(funcs ...OptFn) (*Runner, error) {
 			return nil, err
 		}
 	}
-	shellrunner, err := shell.New(
+	shellrunner, err := shell.New(append([]shell.OptFn{
 		shell.WithWorkDir(opts.WorkDir),
 		shell.WithCommand("npm"),
 		shell.WithArguments([]string{"test", "--", "--reporter=tap"}),
-	)
+	}, opts.ShellOptions...)...)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,28 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+package pytest
+
+import (
+	"context"
+	"fmt"
+
+	testresult "github.com/in-toto/attestation/go/predicates/test_result/v0"
+
+	"github.com/carabiner-dev/beaker/pkg/parsers/junit"
+)
+
+// testSeparator joins the JUnit class and test names, mimicking pytest
+// node ids.
+const testSeparator = "::"
+
+// ParseResults reads the JUnit XML report written by pytest. Tests that
+// failed or errored are recorded as failed, skipped tests are not
+// recorded as passing.
+func (r *Runner) ParseResults(ctx context.Context, att *testresult.TestResult, res []byte) (*testresult.TestResult, error) {
+	parser, err := junit.New(junit.WithSeparator(testSeparator))
+	if err != nil {
+		return nil, fmt.Errorf("creating junit parser: %w", err)
+	}
+	return parser.ParseResults(ctx, att, res)
+}
@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+package pytest
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseResults(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name   string
+		file   string
+		result string
+		passed []string
+		failed []string
+	}{
+		{
+			name:   "testsuites",
+			file:   "testdata/junit.xml",
+			result: "fail",
+			passed: []string{"tests.test_math::test_add", "tests.test_math::test_sub[1-2]", "tests.test_io::test_write"},
+			failed: []string{"tests.test_math.TestDivide::test_by_zero", "tests.test_io::test_read"},
+		},
+		{
+			name:   "testsuite-root",
+			file:   "testdata/junit-legacy.xml",
+			result: "pass",
+			passed: []string{"test_app::test_index", "test_app::test_health"},
+			failed: []string{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			data, err := os.ReadFile(tc.file)
+			require.NoError(t, err)
+
+			r := &Runner{}
+			att, err := r.ParseResults(t.Context(), nil, data)
+			require.NoError(t, err)
+			require.Equal(t, tc.result, att.GetResult())
+			require.Equal(t, tc.passed, att.GetPassedTests())
+			require.Equal(t, tc.failed, att.GetFailedTests())
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+		r := &Runner{}
+		_, err := r.ParseResults(t.Context(), nil, []byte("not xml"))
+		require.Error(t, err)
+	})
+}
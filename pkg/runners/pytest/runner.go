@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+package pytest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"sigs.k8s.io/release-utils/helpers"
+
+	"github.com/carabiner-dev/beaker/pkg/runners/shell"
+)
+
+// ProjectFiles are the files that mark a codebase as a pytest project
+var ProjectFiles = []string{"pyproject.toml", "pytest.ini", "setup.cfg", "tox.ini"}
+
+type Options struct {
+	WorkDir string
+
+	// ShellOptions are applied to the shell runner after the defaults
+	ShellOptions []shell.OptFn
+}
+
+func WithWorkDir(path string) OptFn {
+	return func(o *Options) error {
+		if !helpers.IsDir(path) {
+			return fmt.Errorf("working dir does not exist: %q", path)
+		}
+		o.WorkDir = path
+		return nil
+	}
+}
+
+// WithShellOptions overrides the command, arguments or environment
+// of the underlying shell runner.
+func WithShellOptions(funcs ...shell.OptFn) OptFn {
+	return func(o *Options) error {
+		o.ShellOptions = append(o.ShellOptions, funcs...)
+		return nil
+	}
+}
+
+type OptFn func(*Options) error
+
+// New returns a new pytest runner
+func New(funcs ...OptFn) (*Runner, error) {
+	opts := Options{
+		WorkDir: ".",
+	}
+
+	for _, f := range funcs {
+		if err := f(&opts); err != nil {
+			return nil, err
+		}
+	}
+	shellrunner, err := shell.New(append([]shell.OptFn{
+		shell.WithWorkDir(opts.WorkDir),
+		shell.WithCommand("pytest"),
+		shell.WithArguments([]string{}),
+	}, opts.ShellOptions...)...)
+	if err != nil {
+		return nil, err
+	}
+	return &Runner{
+		Options: opts,
+		runner:  shellrunner,
+	}, nil
+}
+
+// Runner implements a TestRunner that executes pytest and reads the
+// results from the JUnit XML report it writes.
+type Runner struct {
+	Options Options
+	runner  *shell.Runner
+}
+
+// Run runs the tests. Instead of the console output, Run returns the
+// contents of the JUnit XML report written by pytest.
+func (r *Runner) Run(ctx context.Context) (attestation []byte, pass bool, err error) {
+	tmp, err := os.MkdirTemp("", "beaker-pytest-")
+	if err != nil {
+		return nil, false, fmt.Errorf("creating temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmp) //nolint:errcheck
+
+	report := filepath.Join(tmp, "junit.xml")
+
+	// Copy the shell runner to append the report flag to its arguments
+	shellrunner := *r.runner
+	shellrunner.Options.Args = append(slices.Clone(r.runner.Options.Args), "--junitxml="+report)
+
+	if _, pass, err = shellrunner.Run(ctx); err != nil {
+		return nil, false, err
+	}
+
+	data, err := os.ReadFile(report)
+	if err != nil {
+		return nil, false, fmt.Errorf("reading pytest junit report: %w", err)
+	}
+	return data, pass, nil
+}
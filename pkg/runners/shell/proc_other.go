@@ -0,0 +1,21 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+//go:build !unix
+
+package shell
+
+import (
+	"os/exec"
+)
+
+// setProcessGroup is a no-op on platforms without process groups
+func setProcessGroup(*exec.Cmd) {}
+
+// killProcessGroup kills the command process
+func killProcessGroup(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	return cmd.Process.Kill()
+}
@@ -6,16 +6,28 @@ package shell
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
-
-	"sigs.k8s.io/release-utils/command"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
 )
 
+// waitDelay is the time to wait for the output of a killed command to
+// be closed before giving up on it.
+const waitDelay = 5 * time.Second
+
 type Options struct {
 	WorkDir string
 	Command string
 	Args    []string
 	Env     map[string]string
+
+	// CaptureStderr adds the command's standard error to the captured
+	// output, for tools that report progress on stderr.
+	CaptureStderr bool
 }
 
 type OptFn func(*Options) error
@@ -50,6 +62,30 @@ func WithEnv(env map[string]string) OptFn {
 	}
 }
 
+// WithEnvVar sets a variable in the environment of the command, keeping
+// the rest of the variables already set.
+func WithEnvVar(name, value string) OptFn {
+	return func(o *Options) error {
+		if name == "" {
+			return errors.New("environment variable has no name")
+		}
+		if o.Env == nil {
+			o.Env = map[string]string{}
+		}
+		o.Env[name] = value
+		return nil
+	}
+}
+
+// WithCaptureStderr captures the standard error of the command along
+// with its standard output.
+func WithCaptureStderr(capture bool) OptFn {
+	return func(o *Options) error {
+		o.CaptureStderr = capture
+		return nil
+	}
+}
+
 // New returns a new shell runner configured with the passed options
 func New(funcs ...OptFn) (*Runner, error) {
 	opts := Options{
@@ -71,37 +107,85 @@ type Runner struct {
 	Options Options
 }
 
-// Run runs the tests
-func (r *Runner) Run(context.Context) (attestation []byte, pass bool, err error) {
-	var cmd *command.Command
-	if r.Options.WorkDir == "" {
-		cmd = command.New(
-			r.Options.Command,
-			r.Options.Args...,
-		)
-	} else {
-		cmd = command.NewWithWorkDir(
-			r.Options.WorkDir,
-			r.Options.Command,
-			r.Options.Args...,
-		)
+// Run runs the tests. When the context is cancelled or its deadline
+// expires, the command and all the processes it started are killed and
+// the output captured so far is returned with the context error.
+func (r *Runner) Run(ctx context.Context) (attestation []byte, pass bool, err error) {
+	cmd := exec.CommandContext(ctx, r.Options.Command, r.Options.Args...) //nolint:gosec // Running the configured command is the point
+	cmd.Dir = r.Options.WorkDir
+
+	cmd.Env = r.environ()
+
+	// stdout and stderr are copied concurrently, so the buffer is locked
+	b := &syncBuffer{}
+	cmd.Stdout = io.MultiWriter(os.Stdout, b)
+	cmd.Stderr = os.Stderr
+	if r.Options.CaptureStderr {
+		cmd.Stderr = io.MultiWriter(os.Stderr, b)
 	}
 
-	if len(r.Options.Env) > 0 {
-		envs := make([]string, 0, len(r.Options.Env))
-		for k, val := range r.Options.Env {
-			envs = append(envs, fmt.Sprintf("%s=%s", k, val))
-		}
-		cmd = cmd.Env(envs...)
+	// Run the command in its own process group so that cancelling kills
+	// the test binaries it spawns too. If they keep the output pipes open,
+	// stop waiting for them after a while.
+	setProcessGroup(cmd)
+	cmd.Cancel = func() error {
+		return killProcessGroup(cmd)
 	}
+	cmd.WaitDelay = waitDelay
 
-	var b bytes.Buffer
-	cmd = cmd.AddOutputWriter(&b)
+	err = cmd.Run()
+	if err != nil && ctx.Err() != nil {
+		return b.Bytes(), false, fmt.Errorf("running %s: %w", r.Options.Command, ctx.Err())
+	}
 
-	status, err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return b.Bytes(), false, nil
+	}
 	if err != nil {
 		return nil, false, fmt.Errorf("shelling out to command: %w", err)
 	}
 
-	return b.Bytes(), status.Success(), nil
+	return b.Bytes(), true, nil
+}
+
+// Output runs an auxiliary command, such as a tool version query, in the
+// working directory and environment of the runner and returns its output.
+func (r *Runner) Output(ctx context.Context, command string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, command, args...) //nolint:gosec // The commands are set by the runners
+	cmd.Dir = r.Options.WorkDir
+	cmd.Env = r.environ()
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("running %s: %w", command, err)
+	}
+	return out, nil
+}
+
+// environ returns the environment of the commands, the environment of
+// beaker with the runner variables added.
+func (r *Runner) environ() []string {
+	env := os.Environ()
+	for k, val := range r.Options.Env {
+		env = append(env, fmt.Sprintf("%s=%s", k, val))
+	}
+	return env
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent writes
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (sb *syncBuffer) Write(p []byte) (int, error) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.buf.Write(p)
+}
+
+func (sb *syncBuffer) Bytes() []byte {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.buf.Bytes()
 }
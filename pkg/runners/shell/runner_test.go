@@ -0,0 +1,124 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+//go:build unix
+
+package shell
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		r, err := New(
+			WithCommand("sh"),
+			WithArguments([]string{"-c", "echo $GREETING; echo ignored >&2"}),
+			WithEnv(map[string]string{"GREETING": "hello"}),
+		)
+		require.NoError(t, err)
+
+		out, pass, err := r.Run(t.Context())
+		require.NoError(t, err)
+		require.True(t, pass)
+		require.Equal(t, "hello\n", string(out))
+	})
+
+	t.Run("stderr", func(t *testing.T) {
+		t.Parallel()
+		r, err := New(
+			WithCommand("sh"),
+			WithArguments([]string{"-c", "echo captured >&2"}),
+			WithCaptureStderr(true),
+		)
+		require.NoError(t, err)
+
+		out, _, err := r.Run(t.Context())
+		require.NoError(t, err)
+		require.Equal(t, "captured\n", string(out))
+	})
+
+	t.Run("exit-status", func(t *testing.T) {
+		t.Parallel()
+		r, err := New(WithCommand("sh"), WithArguments([]string{"-c", "echo failing; exit 3"}))
+		require.NoError(t, err)
+
+		out, pass, err := r.Run(t.Context())
+		require.NoError(t, err)
+		require.False(t, pass)
+		require.Equal(t, "failing\n", string(out))
+	})
+
+	t.Run("missing-command", func(t *testing.T) {
+		t.Parallel()
+		r, err := New(WithCommand("beaker-this-command-does-not-exist"))
+		require.NoError(t, err)
+
+		_, _, err = r.Run(t.Context())
+		require.Error(t, err)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		t.Parallel()
+		r, err := New(WithCommand("sh"), WithArguments([]string{"-c", "echo started; sleep 30"}))
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(t.Context(), 200*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		out, pass, err := r.Run(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.False(t, pass)
+		require.Less(t, time.Since(start), 10*time.Second)
+		require.Equal(t, "started\n", string(out))
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		t.Parallel()
+		r, err := New(WithCommand("sleep"), WithArguments([]string{"30"}))
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(t.Context())
+		time.AfterFunc(100*time.Millisecond, cancel)
+
+		_, _, err = r.Run(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("process-group", func(t *testing.T) {
+		t.Parallel()
+		// The shell starts a sleep in the background and prints its pid.
+		// Cancelling must kill the child too, not only the shell.
+		r, err := New(WithCommand("sh"), WithArguments([]string{"-c", "sleep 30 & echo $!; wait"}))
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(t.Context(), 500*time.Millisecond)
+		defer cancel()
+
+		out, _, err := r.Run(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		pid, err := strconv.Atoi(strings.TrimSpace(string(out)))
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			// The process is gone or a zombie waiting to be reaped
+			data, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+			if err != nil {
+				return true
+			}
+			_, rest, _ := strings.Cut(string(data), ") ")
+			return strings.HasPrefix(rest, "Z")
+		}, 5*time.Second, 50*time.Millisecond)
+	})
+}
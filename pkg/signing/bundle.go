@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+package signing
+
+import (
+	"crypto"
+	"crypto/x509"
+	"errors"
+	"fmt"
+
+	protobundle "github.com/sigstore/protobuf-specs/gen/pb-go/bundle/v1"
+	protocommon "github.com/sigstore/protobuf-specs/gen/pb-go/common/v1"
+	sdsse "github.com/sigstore/protobuf-specs/gen/pb-go/dsse"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// BundleMediaType is the media type of the sigstore bundles written by beaker
+const BundleMediaType = "application/vnd.dev.sigstore.bundle.v0.3+json"
+
+// NewBundle wraps a signed DSSE envelope in a sigstore bundle. When a
+// certificate is passed, it is included as the verification material,
+// otherwise the bundle carries a hint with the key id of the signer. No
+// transparency log entries are included.
+func NewBundle(env *sdsse.Envelope, signer *Signer, cert *x509.Certificate) (*protobundle.Bundle, error) {
+	if env == nil {
+		return nil, errors.New("no envelope to bundle")
+	}
+
+	pub, err := signer.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+
+	material := &protobundle.VerificationMaterial{}
+	if cert != nil {
+		k, ok := pub.Key.(interface{ Equal(crypto.PublicKey) bool })
+		if !ok || !k.Equal(cert.PublicKey) {
+			return nil, errors.New("certificate does not match the signing key")
+		}
+		material.Content = &protobundle.VerificationMaterial_Certificate{
+			Certificate: &protocommon.X509Certificate{RawBytes: cert.Raw},
+		}
+	} else {
+		material.Content = &protobundle.VerificationMaterial_PublicKey{
+			PublicKey: &protocommon.PublicKeyIdentifier{Hint: pub.ID()},
+		}
+	}
+
+	return &protobundle.Bundle{
+		MediaType:            BundleMediaType,
+		VerificationMaterial: material,
+		Content:              &protobundle.Bundle_DsseEnvelope{DsseEnvelope: env},
+	}, nil
+}
+
+// ParseBundle reads a sigstore bundle and returns its DSSE envelope
+func ParseBundle(data []byte) (*sdsse.Envelope, error) {
+	bundle := &protobundle.Bundle{}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, bundle); err != nil {
+		return nil, fmt.Errorf("parsing bundle: %w", err)
+	}
+	env := bundle.GetDsseEnvelope()
+	if env == nil {
+		return nil, errors.New("bundle does not contain a DSSE envelope")
+	}
+	return env, nil
+}
@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+package signing
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/carabiner-dev/signer/key"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// selfSignedCert returns a certificate for the key, signed by itself
+func selfSignedCert(t *testing.T, priv *key.Private) *x509.Certificate {
+	t.Helper()
+	cs, ok := priv.Key.(crypto.Signer)
+	require.True(t, ok)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "beaker test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, cs.Public(), cs)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
+
+func TestBundle(t *testing.T) {
+	t.Parallel()
+	priv, err := key.NewGenerator().GenerateKeyPair()
+	require.NoError(t, err)
+	signer, err := NewSigner(priv)
+	require.NoError(t, err)
+	otherKey, err := key.NewGenerator().GenerateKeyPair()
+	require.NoError(t, err)
+
+	payload := []byte(`{"_type":"https://in-toto.io/Statement/v1"}`)
+	env, err := signer.SignPayload(PayloadType, payload)
+	require.NoError(t, err)
+
+	t.Run("public-key", func(t *testing.T) {
+		t.Parallel()
+		bundle, err := NewBundle(env, signer, nil)
+		require.NoError(t, err)
+		require.Equal(t, BundleMediaType, bundle.GetMediaType())
+		keyID, err := signer.KeyID()
+		require.NoError(t, err)
+		require.Equal(t, keyID, bundle.GetVerificationMaterial().GetPublicKey().GetHint())
+		require.Equal(t, payload, bundle.GetDsseEnvelope().GetPayload())
+
+		// The envelope read back from the bundle verifies
+		data, err := protojson.Marshal(bundle)
+		require.NoError(t, err)
+		parsed, err := ParseBundle(data)
+		require.NoError(t, err)
+		require.True(t, proto.Equal(env, parsed))
+		pub, err := signer.PublicKey()
+		require.NoError(t, err)
+		verifier, err := NewVerifier(pub)
+		require.NoError(t, err)
+		require.NoError(t, verifier.Verify(parsed))
+	})
+
+	t.Run("certificate", func(t *testing.T) {
+		t.Parallel()
+		cert := selfSignedCert(t, priv)
+		bundle, err := NewBundle(env, signer, cert)
+		require.NoError(t, err)
+		require.Equal(t, cert.Raw, bundle.GetVerificationMaterial().GetCertificate().GetRawBytes())
+	})
+
+	t.Run("certificate-mismatch", func(t *testing.T) {
+		t.Parallel()
+		_, err := NewBundle(env, signer, selfSignedCert(t, otherKey))
+		require.Error(t, err)
+	})
+
+	t.Run("no-envelope", func(t *testing.T) {
+		t.Parallel()
+		_, err := ParseBundle([]byte(`{"mediaType": "` + BundleMediaType + `"}`))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+// Package signing wraps in-toto statements in DSSE envelopes signed with
+// local keys. Keys are read and used through carabiner-dev/signer.
+package signing
+
+import (
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/carabiner-dev/signer/dsse"
+	"github.com/carabiner-dev/signer/key"
+	sdsse "github.com/sigstore/protobuf-specs/gen/pb-go/dsse"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+)
+
+// PayloadType is the DSSE payload type of in-toto statements
+const PayloadType = "application/vnd.in-toto+json"
+
+// Signer signs payloads into DSSE envelopes with a private key
+type Signer struct {
+	key  key.PrivateKeyProvider
+	dsse dsse.Signer
+}
+
+// NewSigner creates a signer from a private key. PEM keys (ECDSA, Ed25519
+// or RSA) and GPG keys are supported.
+func NewSigner(pk key.PrivateKeyProvider) (*Signer, error) {
+	if pk == nil {
+		return nil, errors.New("no private key to sign with")
+	}
+	if _, err := pk.PrivateKey(); err != nil {
+		return nil, fmt.Errorf("reading private key: %w", err)
+	}
+	return &Signer{key: pk, dsse: dsse.NewSigner()}, nil
+}
+
+// LoadSigner reads a private key from a file. Encrypted keys (PKCS#8,
+// sigstore/cosign or GPG) are decrypted with passphrase.
+func LoadSigner(path string, passphrase []byte) (*Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading key file: %w", err)
+	}
+	return ParseSigner(data, passphrase)
+}
+
+// ParseSigner creates a signer from PEM or GPG private key data
+func ParseSigner(data, passphrase []byte) (*Signer, error) {
+	data, err := decryptPEM(data, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	pk, err := key.NewParser().ParsePrivateKeyProvider(data, key.WithPassphrase(string(passphrase)))
+	if err != nil {
+		return nil, err
+	}
+	return NewSigner(pk)
+}
+
+// decryptPEM returns encrypted PEM private keys decrypted with passphrase
+// as unencrypted PKCS#8 keys. Other key data is returned unchanged, GPG
+// keys are decrypted by the key parser.
+func decryptPEM(data, passphrase []byte) ([]byte, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || (!strings.HasPrefix(block.Type, "ENCRYPTED ") && !isLegacyEncrypted(block)) {
+		return data, nil
+	}
+	priv, err := cryptoutils.UnmarshalPEMToPrivateKey(data, func(bool) ([]byte, error) {
+		if len(passphrase) == 0 {
+			return nil, errors.New("key is encrypted but no passphrase was provided")
+		}
+		return passphrase, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("decrypting private key: %w", err)
+	}
+	return cryptoutils.MarshalPrivateKeyToPEM(priv)
+}
+
+// isLegacyEncrypted reports if block is encrypted with the RFC 1423 headers
+func isLegacyEncrypted(block *pem.Block) bool {
+	_, ok := block.Headers["DEK-Info"]
+	return ok
+}
+
+// SignPayload wraps payload in a DSSE envelope signed with the key. The
+// signature records the key id.
+func (s *Signer) SignPayload(payloadType string, payload []byte) (*sdsse.Envelope, error) {
+	env, err := s.dsse.WrapPayload(payloadType, payload)
+	if err != nil {
+		return nil, fmt.Errorf("wrapping payload: %w", err)
+	}
+	if err := s.dsse.Sign(env, []key.PrivateKeyProvider{s.key}); err != nil {
+		return nil, fmt.Errorf("signing payload: %w", err)
+	}
+
+	keyID, err := s.KeyID()
+	if err != nil {
+		return nil, err
+	}
+	for _, sig := range env.GetSignatures() {
+		sig.Keyid = keyID
+	}
+	return env, nil
+}
+
+// KeyID returns the identifier of the signing key
+func (s *Signer) KeyID() (string, error) {
+	pub, err := s.PublicKey()
+	if err != nil {
+		return "", err
+	}
+	return pub.ID(), nil
+}
+
+// PublicKey returns the public key of the signer
+func (s *Signer) PublicKey() (*key.Public, error) {
+	// GPG keys report their fingerprint as the public key id
+	if pp, ok := s.key.(key.PublicKeyProvider); ok {
+		return pp.PublicKey()
+	}
+	priv, err := s.key.PrivateKey()
+	if err != nil {
+		return nil, fmt.Errorf("reading private key: %w", err)
+	}
+	pub, err := priv.PublicKey()
+	if err != nil {
+		return nil, fmt.Errorf("deriving public key: %w", err)
+	}
+	return pub, nil
+}
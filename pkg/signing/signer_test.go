@@ -0,0 +1,176 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+package signing
+
+import (
+	"crypto"
+	"crypto/elliptic"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/carabiner-dev/signer/key"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/stretchr/testify/require"
+	"github.com/youmark/pkcs8"
+)
+
+func TestSignPayload(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name string
+		opts []key.FnGenOpt
+	}{
+		{"ecdsa-p256", []key.FnGenOpt{key.WithKeyType(key.ECDSA)}},
+		{"ecdsa-p384", []key.FnGenOpt{key.WithKeyType(key.ECDSA), key.WithEllipticCurve(elliptic.P384())}},
+		{"ed25519", []key.FnGenOpt{key.WithKeyType(key.ED25519)}},
+		{"rsa", []key.FnGenOpt{key.WithKeyType(key.RSA), key.WithKeyLength(2048)}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			priv, err := key.NewGenerator().GenerateKeyPair(tc.opts...)
+			require.NoError(t, err)
+
+			// Keys are read from their PEM encoding
+			signer, err := ParseSigner([]byte(priv.Data), nil)
+			require.NoError(t, err)
+
+			payload := []byte(`{"_type":"https://in-toto.io/Statement/v1"}`)
+			env, err := signer.SignPayload(PayloadType, payload)
+			require.NoError(t, err)
+			require.Equal(t, PayloadType, env.GetPayloadType())
+			require.Equal(t, payload, env.GetPayload())
+			require.Len(t, env.GetSignatures(), 1)
+
+			keyID, err := signer.KeyID()
+			require.NoError(t, err)
+			require.NotEmpty(t, keyID)
+			require.Equal(t, keyID, env.GetSignatures()[0].GetKeyid())
+
+			// Verifiers read PKIX public keys
+			pub, err := signer.PublicKey()
+			require.NoError(t, err)
+			der, err := x509.MarshalPKIXPublicKey(pub.Key)
+			require.NoError(t, err)
+			verifier, err := ParseVerifier(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+			require.NoError(t, err)
+			require.NoError(t, verifier.Verify(env))
+
+			// Tampering with the payload breaks the signature
+			env.Payload = []byte(`{}`)
+			require.Error(t, verifier.Verify(env))
+		})
+	}
+}
+
+func TestVerifyOtherKey(t *testing.T) {
+	t.Parallel()
+	priv, err := key.NewGenerator().GenerateKeyPair()
+	require.NoError(t, err)
+	signer, err := NewSigner(priv)
+	require.NoError(t, err)
+	other, err := key.NewGenerator().GenerateKeyPair()
+	require.NoError(t, err)
+	otherPub, err := other.PublicKey()
+	require.NoError(t, err)
+	verifier, err := NewVerifier(otherPub)
+	require.NoError(t, err)
+
+	env, err := signer.SignPayload(PayloadType, []byte("{}"))
+	require.NoError(t, err)
+	require.Error(t, verifier.Verify(env))
+
+	_, err = NewVerifier()
+	require.Error(t, err)
+}
+
+func TestParseEncryptedSigner(t *testing.T) {
+	t.Parallel()
+	passphrase := []byte("s3cr3t")
+	for _, tc := range []struct {
+		name    string
+		opts    []key.FnGenOpt
+		encrypt func(t *testing.T, priv crypto.PrivateKey) []byte
+	}{
+		{"sigstore-ecdsa", []key.FnGenOpt{key.WithKeyType(key.ECDSA)}, sigstoreEncrypt},
+		{"sigstore-ed25519", []key.FnGenOpt{key.WithKeyType(key.ED25519)}, sigstoreEncrypt},
+		{"sigstore-rsa", []key.FnGenOpt{key.WithKeyType(key.RSA), key.WithKeyLength(2048)}, sigstoreEncrypt},
+		{"pkcs8-ecdsa", []key.FnGenOpt{key.WithKeyType(key.ECDSA)}, pkcs8Encrypt},
+		{"pkcs8-ed25519", []key.FnGenOpt{key.WithKeyType(key.ED25519)}, pkcs8Encrypt},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			generated, err := key.NewGenerator().GenerateKeyPair(tc.opts...)
+			require.NoError(t, err)
+			priv, err := cryptoutils.UnmarshalPEMToPrivateKey([]byte(generated.Data), nil)
+			require.NoError(t, err)
+			data := tc.encrypt(t, priv)
+
+			_, err = ParseSigner(data, nil)
+			require.Error(t, err)
+			_, err = ParseSigner(data, []byte("wrong"))
+			require.Error(t, err)
+
+			signer, err := ParseSigner(data, passphrase)
+			require.NoError(t, err)
+			env, err := signer.SignPayload(PayloadType, []byte("{}"))
+			require.NoError(t, err)
+
+			pub, err := generated.PublicKey()
+			require.NoError(t, err)
+			der, err := x509.MarshalPKIXPublicKey(pub.Key)
+			require.NoError(t, err)
+			verifier, err := ParseVerifier(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+			require.NoError(t, err)
+			require.NoError(t, verifier.Verify(env))
+		})
+	}
+}
+
+// sigstoreEncrypt encodes priv as an encrypted sigstore (cosign) PEM key
+func sigstoreEncrypt(t *testing.T, priv crypto.PrivateKey) []byte {
+	t.Helper()
+	der, err := cryptoutils.MarshalPrivateKeyToEncryptedDER(priv, cryptoutils.StaticPasswordFunc([]byte("s3cr3t")))
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: string(cryptoutils.EncryptedSigstorePrivateKeyPEMType), Bytes: der})
+}
+
+// pkcs8Encrypt encodes priv as an encrypted PKCS#8 PEM key
+func pkcs8Encrypt(t *testing.T, priv crypto.PrivateKey) []byte {
+	t.Helper()
+	der, err := pkcs8.MarshalPrivateKey(priv, []byte("s3cr3t"), nil)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED PRIVATE KEY", Bytes: der})
+}
+
+func TestParseSignerInvalid(t *testing.T) {
+	t.Parallel()
+	_, err := ParseSigner([]byte("not a key"), nil)
+	require.Error(t, err)
+	_, err = NewSigner(nil)
+	require.Error(t, err)
+}
+
+func TestCertificateVerifier(t *testing.T) {
+	t.Parallel()
+	priv, err := key.NewGenerator().GenerateKeyPair()
+	require.NoError(t, err)
+	signer, err := NewSigner(priv)
+	require.NoError(t, err)
+	env, err := signer.SignPayload(PayloadType, []byte("{}"))
+	require.NoError(t, err)
+
+	verifier, err := NewCertificateVerifier(selfSignedCert(t, priv))
+	require.NoError(t, err)
+	require.NoError(t, verifier.Verify(env))
+
+	other, err := key.NewGenerator().GenerateKeyPair()
+	require.NoError(t, err)
+	verifier, err = NewCertificateVerifier(selfSignedCert(t, other))
+	require.NoError(t, err)
+	require.Error(t, verifier.Verify(env))
+
+	_, err = NewCertificateVerifier(nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2026 Carabiner Systems, Inc
+
+package signing
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/carabiner-dev/signer/dsse"
+	"github.com/carabiner-dev/signer/key"
+	"github.com/carabiner-dev/signer/options"
+	sdsse "github.com/sigstore/protobuf-specs/gen/pb-go/dsse"
+)
+
+// Verifier checks DSSE signatures made with a private key
+type Verifier struct {
+	keys []key.PublicKeyProvider
+	dsse dsse.Verifier
+}
+
+// NewVerifier creates a verifier that accepts signatures made with any
+// of the keys.
+func NewVerifier(keys ...key.PublicKeyProvider) (*Verifier, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("no public keys to verify with")
+	}
+	return &Verifier{keys: keys, dsse: &dsse.DefaultVerifier{}}, nil
+}
+
+// LoadVerifier reads a PEM or GPG public key from a file
+func LoadVerifier(path string) (*Verifier, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading key file: %w", err)
+	}
+	return ParseVerifier(data)
+}
+
+// ParseVerifier creates a verifier from PEM or GPG public key data
+func ParseVerifier(data []byte) (*Verifier, error) {
+	pub, err := key.NewParser().ParsePublicKeyProvider(data)
+	if err != nil {
+		return nil, err
+	}
+	return NewVerifier(pub)
+}
+
+// NewCertificateVerifier creates a verifier for the public key of a
+// certificate. The certificate chain and identity are not checked.
+func NewCertificateVerifier(cert *x509.Certificate) (*Verifier, error) {
+	if cert == nil {
+		return nil, errors.New("no certificate to verify with")
+	}
+	der, err := x509.MarshalPKIXPublicKey(cert.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling certificate public key: %w", err)
+	}
+	return ParseVerifier(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+// Verify checks that a key of the verifier signed the envelope
+func (v *Verifier) Verify(env *sdsse.Envelope) error {
+	if env == nil {
+		return errors.New("no envelope to verify")
+	}
+	opts := options.DefaultVerifier
+	kv, err := v.dsse.BuildKeyVerifier(&opts)
+	if err != nil {
+		return fmt.Errorf("building key verifier: %w", err)
+	}
+	res, err := v.dsse.RunVerification(&opts, kv, env, v.keys)
+	if err != nil {
+		return fmt.Errorf("verifying envelope: %w", err)
+	}
+	if !res.Verified {
+		return errors.New("no signature matches the public key")
+	}
+	return nil
+}
+
+// LoadCertificate reads a PEM encoded certificate from a file
+func LoadCertificate(path string) (*x509.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading certificate: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("no certificate found in file")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing certificate: %w", err)
+	}
+	return cert, nil
+}